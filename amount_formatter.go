@@ -28,6 +28,8 @@ const (
 	DisplayCode
 	// DisplayNone shows nothing, hiding the currency.
 	DisplayNone
+	// DisplayNarrowSymbol shows the narrow currency symbol: $ instead of $US.
+	DisplayNarrowSymbol
 )
 
 var localDigits = map[string]string{
@@ -331,6 +333,8 @@ func (f *AmountFormatter) formatCurrency(currencyCode string, options *Formattin
 		} else {
 			formatted, _ = GetSymbol(currencyCode, f.locale)
 		}
+	case DisplayNarrowSymbol:
+		formatted, _ = GetNarrowSymbol(currencyCode, f.locale)
 	case DisplayCode:
 		formatted = currencyCode
 	default:
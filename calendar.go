@@ -0,0 +1,593 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/rande/golocales/dto"
+)
+
+// SupportedCalendars are the calendar systems with a date arithmetic. The
+// islamic-civil calendar is the tabular one. The other islamic variants
+// (islamic, islamic-umalqura, islamic-rgsa) are based on the observation or
+// the calculation of the new moon, they are approximated with the tabular
+// arithmetic: a date can differ by one or two days from the official one.
+var SupportedCalendars = []string{"gregorian", "buddhist", "japanese", "roc", "islamic-civil", "islamic", "islamic-umalqura", "islamic-rgsa", "persian", "hebrew"}
+
+// The julian day number of 1970-01-01, the dates are converted through
+// their julian day.
+const unixEpochJulianDay = 2440588
+
+// calendarDate is a date in a calendar system, the month is the position
+// of the month in the year, starting at 1.
+type calendarDate struct {
+	Era     int
+	Year    int
+	Month   int
+	Day     int
+	YearDay int
+	// MonthLabel is the index of the month in the labels, starting at 1,
+	// it differs from Month for the hebrew calendar as Adar I only exists
+	// in the leap years.
+	MonthLabel int
+	// Leap is true for a leap month: Adar II
+	Leap bool
+}
+
+// NormalizeCalendar returns the calendar system name used by the locales,
+// the BCP 47 names are converted: gregory is gregorian. The value is empty
+// if the calendar is not supported.
+func NormalizeCalendar(calendar string) string {
+	calendar = strings.ToLower(calendar)
+	if calendar == "gregory" {
+		return "gregorian"
+	}
+
+	for _, c := range SupportedCalendars {
+		if c == calendar {
+			return c
+		}
+	}
+
+	return ""
+}
+
+// GetCalendarFromTag returns the calendar requested by the -u-ca- keyword of
+// the language tag, ie: "th-TH-u-ca-buddhist" is buddhist. The value is
+// empty if the tag does not request a supported calendar.
+func GetCalendarFromTag(tag string) string {
+	parts := strings.Split(strings.ReplaceAll(tag, "_", "-"), "-")
+
+	extension := false
+	for i, part := range parts {
+		part = strings.ToLower(part)
+
+		if len(part) == 1 {
+			// a singleton starts an extension
+			extension = part == "u"
+			continue
+		}
+
+		if extension && part == "ca" {
+			// the type can have several subtags: islamic-umalqura
+			value := []string{}
+			for _, subtag := range parts[i+1:] {
+				if len(subtag) <= 2 {
+					break
+				}
+
+				value = append(value, subtag)
+			}
+
+			return NormalizeCalendar(strings.Join(value, "-"))
+		}
+	}
+
+	return ""
+}
+
+// GetPreferredCalendar returns the first supported calendar used in the
+// territory, ie: buddhist for TH.
+func GetPreferredCalendar(territory string) string {
+	for _, calendar := range dto.GetSupplemental().GetCalendarPreferences(territory) {
+		if calendar := NormalizeCalendar(calendar); calendar != "" {
+			return calendar
+		}
+	}
+
+	return DefaultCalendar
+}
+
+// getLocaleCalendar returns the preferred calendar of the locale's territory.
+func getLocaleCalendar(locale *dto.Locale) string {
+	return GetPreferredCalendar(GetLocaleTerritory(locale))
+}
+
+// getCalendarFallbacks returns the calendars used to find a pattern, the
+// generic calendar defines the patterns shared by the non gregorian ones.
+func getCalendarFallbacks(calendar string) []string {
+	if calendar == DefaultCalendar {
+		return []string{calendar}
+	}
+
+	if labels := getLabelCalendar(calendar); labels != calendar {
+		return []string{calendar, labels, "generic", DefaultCalendar}
+	}
+
+	return []string{calendar, "generic", DefaultCalendar}
+}
+
+// getLabelCalendar returns the calendar defining the labels and the
+// patterns, the islamic variants use the ones of the islamic calendar.
+func getLabelCalendar(calendar string) string {
+	if strings.HasPrefix(calendar, "islamic-") {
+		return "islamic"
+	}
+
+	return calendar
+}
+
+func getCalendarPattern(locale *dto.Locale, calendar, name string) string {
+	for _, c := range getCalendarFallbacks(calendar) {
+		if pattern := locale.GetCalendarPattern(c, name); pattern != "" {
+			return pattern
+		}
+	}
+
+	return ""
+}
+
+// getCalendarSkeletons merges the skeletons of the fallback calendars, the
+// calendar usually only defines the skeletons displaying the era.
+func getCalendarSkeletons(locale *dto.Locale, calendar string) map[string]string {
+	skeletons := map[string]string{}
+
+	fallbacks := getCalendarFallbacks(calendar)
+	for i := len(fallbacks) - 1; i >= 0; i-- {
+		for skeleton, pattern := range locale.GetCalendarSkeletons(fallbacks[i]) {
+			skeletons[skeleton] = pattern
+		}
+	}
+
+	return skeletons
+}
+
+func getCalendarIntervals(locale *dto.Locale, calendar string) map[string]map[string]string {
+	intervals := map[string]map[string]string{}
+
+	fallbacks := getCalendarFallbacks(calendar)
+	for i := len(fallbacks) - 1; i >= 0; i-- {
+		for skeleton, patterns := range locale.GetCalendarIntervals(fallbacks[i]) {
+			intervals[skeleton] = patterns
+		}
+	}
+
+	return intervals
+}
+
+type japaneseEra struct {
+	Type  int
+	Start time.Time
+}
+
+// japaneseEras are the modern eras of the japanese calendar, the type is
+// the index of the era in the labels.
+var japaneseEras = []japaneseEra{
+	{236, time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC)},       // Reiwa
+	{235, time.Date(1989, time.January, 8, 0, 0, 0, 0, time.UTC)},   // Heisei
+	{234, time.Date(1926, time.December, 25, 0, 0, 0, 0, time.UTC)}, // Showa
+	{233, time.Date(1912, time.July, 30, 0, 0, 0, 0, time.UTC)},     // Taisho
+	{232, time.Date(1868, time.September, 8, 0, 0, 0, 0, time.UTC)}, // Meiji
+}
+
+// resolveCalendar returns the calendar used to format the time, the dates
+// before the Meiji era are formatted with the gregorian calendar.
+func resolveCalendar(calendar string, t time.Time) string {
+	if calendar == "japanese" && wallClock(t).Before(japaneseEras[len(japaneseEras)-1].Start) {
+		return DefaultCalendar
+	}
+
+	return calendar
+}
+
+// toCalendarDate converts the date of the wall clock of t into the calendar.
+func toCalendarDate(calendar string, t time.Time) calendarDate {
+	year, month, day := t.Date()
+
+	date := calendarDate{
+		Era:        1,
+		Year:       year,
+		Month:      int(month),
+		Day:        day,
+		YearDay:    t.YearDay(),
+		MonthLabel: int(month),
+	}
+
+	if year <= 0 {
+		date.Era, date.Year = 0, 1-year
+	}
+
+	switch calendar {
+	case "buddhist":
+		// the buddhist era starts in 543 BC
+		date.Era, date.Year = 0, year+543
+	case "roc":
+		date.Era, date.Year = 1, year-1911
+		if year <= 1911 {
+			date.Era, date.Year = 0, 1912-year
+		}
+	case "japanese":
+		wall := wallClock(t)
+		for _, era := range japaneseEras {
+			if !wall.Before(era.Start) {
+				date.Era, date.Year = era.Type, year-era.Start.Year()+1
+
+				break
+			}
+		}
+	case "persian":
+		date = toPersianDate(julianDay(t))
+	case "islamic-civil", "islamic", "islamic-umalqura", "islamic-rgsa":
+		// the observational variants use the tabular arithmetic
+		date = toIslamicDate(julianDay(t))
+	case "hebrew":
+		date = toHebrewDate(julianDay(t))
+	}
+
+	return date
+}
+
+// julianDay returns the julian day number of the wall clock date of t.
+func julianDay(t time.Time) int {
+	return int(floorDiv(wallClock(t).Unix(), 86400)) + unixEpochJulianDay
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+
+	return q
+}
+
+// The first day of the persian months, the first six months have 31 days,
+// the next five 30 days and the last one 29 or 30 days.
+var persianMonthStart = []int{0, 31, 62, 93, 124, 155, 186, 216, 246, 276, 306, 336}
+
+// toPersianDate uses the 33 years arithmetic cycle, the epoch is the
+// 1 Farvardin 1 AP: 622-03-19.
+func toPersianDate(jd int) calendarDate {
+	days := int64(jd - 1948320)
+
+	year := 1 + floorDiv(33*days+3, 12053)
+	newYear := 365*(year-1) + floorDiv(8*year+21, 33)
+
+	yearDay := int(days - newYear)
+
+	month := (yearDay - 6) / 30
+	if yearDay < 216 {
+		month = yearDay / 31
+	}
+
+	return calendarDate{
+		Year:       int(year),
+		Month:      month + 1,
+		Day:        yearDay - persianMonthStart[month] + 1,
+		YearDay:    yearDay + 1,
+		MonthLabel: month + 1,
+	}
+}
+
+// toIslamicDate uses the tabular civil calendar, with 11 leap years in a
+// 30 years cycle, the epoch is the 1 Muharram 1 AH: 622-07-16.
+func toIslamicDate(jd int) calendarDate {
+	days := int64(jd - 1948440)
+
+	year := floorDiv(30*days+10646, 10631)
+
+	month := int64(math.Ceil(float64(days-29-islamicMonthStart(year, 0)) / 29.5))
+	month = min(max(month, 0), 11)
+
+	return calendarDate{
+		Year:       int(year),
+		Month:      int(month) + 1,
+		Day:        int(days-islamicMonthStart(year, month)) + 1,
+		YearDay:    int(days-islamicMonthStart(year, 0)) + 1,
+		MonthLabel: int(month) + 1,
+	}
+}
+
+// islamicMonthStart returns the number of days from the epoch to the first
+// day of the month, the month starts at 0.
+func islamicMonthStart(year, month int64) int64 {
+	return int64(math.Ceil(29.5*float64(month))) + (year-1)*354 + floorDiv(3+11*year, 30)
+}
+
+// The julian day of the 1 Tishri 1 AM: 3761-10-07 BC (julian calendar).
+const hebrewEpoch = 347998
+
+func isHebrewLeapYear(year int64) bool {
+	return (7*year+1)%19 < 7
+}
+
+// hebrewElapsedDays returns the number of days from the epoch to the new
+// year, computed from the molad (the mean new moon) of Tishri.
+func hebrewElapsedDays(year int64) int64 {
+	months := floorDiv(235*year-234, 19)
+	parts := 12084 + 13753*months
+	day := 29*months + floorDiv(parts, 25920)
+
+	if (3*(day+1))%7 < 3 {
+		return day + 1
+	}
+
+	return day
+}
+
+// hebrewNewYear returns the julian day of the 1 Tishri of the year, the
+// new year is delayed so the year has a valid length.
+func hebrewNewYear(year int64) int64 {
+	previous, current, next := hebrewElapsedDays(year-1), hebrewElapsedDays(year), hebrewElapsedDays(year+1)
+
+	switch {
+	case next-current == 356:
+		current += 2
+	case current-previous == 382:
+		current++
+	}
+
+	return hebrewEpoch + current
+}
+
+// hebrewMonthLengths returns the length of the months of the year, from
+// Tishri to Elul, indexed as the CLDR labels: Adar I (6) is empty in a
+// common year.
+func hebrewMonthLengths(year int64) []int {
+	length := hebrewNewYear(year+1) - hebrewNewYear(year)
+
+	months := []int{30, 29, 30, 29, 30, 30, 29, 30, 29, 30, 29, 30, 29}
+
+	if length%10 == 5 {
+		// the long Heshvan
+		months[1] = 30
+	}
+
+	if length%10 == 3 {
+		// the short Kislev
+		months[2] = 29
+	}
+
+	if !isHebrewLeapYear(year) {
+		months[5] = 0
+	}
+
+	return months
+}
+
+// toHebrewDate uses the arithmetic hebrew calendar, the months are counted
+// from Tishri, so the year changes in the autumn.
+func toHebrewDate(jd int) calendarDate {
+	day := int64(jd)
+
+	year := floorDiv(98496*(day-hebrewEpoch), 35975351) + 1
+	for hebrewNewYear(year) > day {
+		year--
+	}
+
+	for hebrewNewYear(year+1) <= day {
+		year++
+	}
+
+	yearDay := int(day - hebrewNewYear(year))
+
+	date := calendarDate{
+		Year:    int(year),
+		YearDay: yearDay + 1,
+	}
+
+	leap := isHebrewLeapYear(year)
+
+	remaining := yearDay
+	for i, length := range hebrewMonthLengths(year) {
+		if length == 0 {
+			continue
+		}
+
+		date.Month++
+
+		if remaining < length {
+			date.Day = remaining + 1
+			date.MonthLabel = i + 1
+			// Adar is Adar II in a leap year
+			date.Leap = leap && i == 6
+
+			break
+		}
+
+		remaining -= length
+	}
+
+	return date
+}
+
+// fromCalendarDate returns the gregorian date of a date of the calendar, at
+// midnight UTC. The month is the position of the month in the year, or the
+// MonthLabel if Month is 0. The value is false if the date does not exist
+// in the calendar: 30 esfand 1402 or an unknown japanese era.
+func fromCalendarDate(calendar string, date calendarDate) (time.Time, bool) {
+	year := int64(date.Year)
+
+	month := date.Month
+	if month == 0 {
+		month = date.MonthLabel
+		if calendar == "hebrew" {
+			// the position of the label, Adar I is missing in a common year
+			month = 0
+			for i, length := range hebrewMonthLengths(year) {
+				if length > 0 && i < date.MonthLabel {
+					month++
+				}
+			}
+
+			if date.MonthLabel == 6 && !isHebrewLeapYear(year) {
+				return time.Time{}, false
+			}
+		}
+	}
+
+	if month < 1 || date.Day < 1 {
+		return time.Time{}, false
+	}
+
+	var jd int64
+	switch calendar {
+	case "persian":
+		if month > 12 {
+			return time.Time{}, false
+		}
+
+		jd = 1948320 + 365*(year-1) + floorDiv(8*year+21, 33) + int64(persianMonthStart[month-1]+date.Day-1)
+	case "islamic-civil", "islamic", "islamic-umalqura", "islamic-rgsa":
+		if month > 12 {
+			return time.Time{}, false
+		}
+
+		jd = 1948440 + islamicMonthStart(year, int64(month-1)) + int64(date.Day-1)
+	case "hebrew":
+		jd = hebrewNewYear(year) + int64(date.Day-1)
+
+		position := 0
+		for _, length := range hebrewMonthLengths(year) {
+			if length == 0 {
+				continue
+			}
+
+			if position++; position == month {
+				break
+			}
+
+			jd += int64(length)
+		}
+
+		if position != month {
+			return time.Time{}, false
+		}
+	default:
+		switch calendar {
+		case "buddhist":
+			year -= 543
+		case "roc":
+			year += 1911
+			if date.Era == 0 {
+				year = 1912 - int64(date.Year)
+			}
+		case "japanese":
+			found := false
+			for _, era := range japaneseEras {
+				if era.Type == date.Era {
+					year, found = year+int64(era.Start.Year())-1, true
+				}
+			}
+
+			if !found {
+				return time.Time{}, false
+			}
+		default:
+			if date.Era == 0 {
+				// there is no year 0: 1 BC is the year 0
+				year = 1 - year
+			}
+		}
+
+		if month > 12 {
+			return time.Time{}, false
+		}
+
+		t := time.Date(int(year), time.Month(month), date.Day, 0, 0, 0, 0, time.UTC)
+
+		return t, t.Day() == date.Day
+	}
+
+	t := time.Unix((jd-unixEpochJulianDay)*86400, 0).UTC()
+
+	// the day does not exist if the date is in the next month
+	converted := toCalendarDate(calendar, t)
+
+	return t, converted.Day == date.Day && converted.Month == month
+}
+
+// formatCalendarField formats the date fields depending on the calendar
+// system, the other fields are the same for all the calendars.
+func formatCalendarField(locale *dto.Locale, calendar string, item patternItem, t time.Time) (string, bool) {
+	date := toCalendarDate(calendar, t)
+
+	switch item.Field {
+	case 'G':
+		return getEraLabel(locale, calendar, item.Count, date.Era), true
+	case 'y':
+		return formatYear(date.Year, item.Count), true
+	case 'u':
+		// the extended year of the japanese and roc calendars is the
+		// gregorian year
+		if calendar == "japanese" || calendar == "roc" {
+			return "", false
+		}
+
+		return formatYear(date.Year, item.Count), true
+	case 'M', 'L':
+		if item.Count <= 2 {
+			return fmt.Sprintf("%0*d", item.Count, date.Month), true
+		}
+
+		context := "format"
+		if item.Field == 'L' {
+			context = "stand-alone"
+		}
+
+		if date.Leap {
+			width := labelWidth(item.Count)
+			if labels := locale.GetCalendarLabels(calendar, fmt.Sprintf("m_%s_%s_leap", context, width)); date.MonthLabel <= len(labels) && labels[date.MonthLabel-1] != "" {
+				return labels[date.MonthLabel-1], true
+			}
+
+			if labels := locale.GetCalendarLabels(calendar, fmt.Sprintf("m_format_%s_leap", width)); date.MonthLabel <= len(labels) && labels[date.MonthLabel-1] != "" {
+				return labels[date.MonthLabel-1], true
+			}
+		}
+
+		return getDateLabel(locale, calendar, "m", context, item.Count, date.MonthLabel-1), true
+	case 'd':
+		return fmt.Sprintf("%0*d", min(item.Count, 2), date.Day), true
+	case 'D':
+		return fmt.Sprintf("%0*d", min(item.Count, 3), date.YearDay), true
+	case 'F':
+		return fmt.Sprintf("%d", (date.Day-1)/7+1), true
+	case 'Q', 'q':
+		if calendar == "hebrew" {
+			// the quarters are not defined for a 13 months year
+			return "", true
+		}
+
+		quarter := (date.Month + 2) / 3
+		if item.Count <= 2 {
+			return fmt.Sprintf("%0*d", item.Count, quarter), true
+		}
+
+		context := "format"
+		if item.Field == 'q' {
+			context = "stand-alone"
+		}
+
+		return getDateLabel(locale, calendar, "q", context, item.Count, quarter-1), true
+	}
+
+	return "", false
+}
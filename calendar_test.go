@@ -0,0 +1,98 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rande/golocales"
+	"github.com/rande/golocales/locales/fr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetCalendarFromTag(t *testing.T) {
+	assert.Equal(t, "buddhist", golocales.GetCalendarFromTag("th-TH-u-ca-buddhist"))
+	assert.Equal(t, "gregorian", golocales.GetCalendarFromTag("en-US-u-ca-gregory"))
+	assert.Equal(t, "islamic-civil", golocales.GetCalendarFromTag("ar-SA-u-ca-islamic-civil-nu-arab"))
+	assert.Equal(t, "islamic-umalqura", golocales.GetCalendarFromTag("ar-SA-u-ca-islamic-umalqura"))
+	assert.Equal(t, "islamic", golocales.GetCalendarFromTag("ar-SA-u-ca-islamic"))
+	assert.Equal(t, "", golocales.GetCalendarFromTag("ar-SA-u-ca-islamic-tbla"))
+	assert.Equal(t, "japanese", golocales.GetCalendarFromTag("ja_JP_u_ca_japanese"))
+	assert.Equal(t, "", golocales.GetCalendarFromTag("fr-FR"))
+	assert.Equal(t, "", golocales.GetCalendarFromTag("fr-FR-x-ca-buddhist"))
+	assert.Equal(t, "", golocales.GetCalendarFromTag("zh-u-ca-chinese"))
+}
+
+func Test_GetPreferredCalendar(t *testing.T) {
+	assert.Equal(t, "gregorian", golocales.GetPreferredCalendar("FR"))
+	assert.Equal(t, "buddhist", golocales.GetPreferredCalendar("TH"))
+	assert.Equal(t, "persian", golocales.GetPreferredCalendar("IR"))
+	// approximated with the tabular arithmetic
+	assert.Equal(t, "islamic-umalqura", golocales.GetPreferredCalendar("SA"))
+}
+
+func Test_DateFormatter_Calendars(t *testing.T) {
+	formatter := golocales.NewDateFormatter(fr.GetLocale())
+	assert.Equal(t, "gregorian", formatter.GetCalendar())
+
+	cases := []struct {
+		calendar string
+		date     time.Time
+		expected string
+	}{
+		{"gregorian", time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), "20 mars 2024"},
+		{"buddhist", time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), "20 mars 2567 E. B."},
+		{"roc", time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), "20 mars 113 RdC"},
+		{"japanese", time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), "20 mars 6 Reiwa"},
+		{"japanese", time.Date(1989, 1, 7, 10, 0, 0, 0, time.UTC), "7 janvier 64 Shōwa"},
+		// before the Meiji era
+		{"japanese", time.Date(1800, 1, 7, 10, 0, 0, 0, time.UTC), "7 janvier 1800 ap. J.-C."},
+		{"persian", time.Date(2024, 3, 19, 10, 0, 0, 0, time.UTC), "29 esfand 1402 A. P."},
+		{"persian", time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), "1 farvardin 1403 A. P."},
+		{"islamic-civil", time.Date(1979, 11, 21, 10, 0, 0, 0, time.UTC), "1 mouharram 1400 AH"},
+		{"islamic-civil", time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), "10 ramadan 1445 AH"},
+		// the observational variants use the tabular arithmetic
+		{"islamic-umalqura", time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), "10 ramadan 1445 AH"},
+		{"islamic", time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), "10 ramadan 1445 AH"},
+		{"hebrew", time.Date(2024, 10, 3, 10, 0, 0, 0, time.UTC), "1 tichri 5785 A. M."},
+		{"hebrew", time.Date(2024, 4, 23, 10, 0, 0, 0, time.UTC), "15 nissan 5784 A. M."},
+		// 5784 is a leap year
+		{"hebrew", time.Date(2024, 2, 20, 10, 0, 0, 0, time.UTC), "11 adar I 5784 A. M."},
+		{"hebrew", time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), "10 adar II 5784 A. M."},
+		{"hebrew", time.Date(2023, 3, 20, 10, 0, 0, 0, time.UTC), "27 adar 5783 A. M."},
+	}
+
+	for _, c := range cases {
+		assert.NoError(t, formatter.SetCalendar(c.calendar))
+		assert.Equal(t, c.expected, formatter.FormatDateTime(c.date, "long", "", time.UTC), c.calendar)
+	}
+
+	assert.NoError(t, formatter.SetCalendar("hebrew"))
+	date := time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, "mercredi 10 adar II 5784 A. M. à 10:00", formatter.FormatDateTime(date, "full", "short", time.UTC))
+	assert.Equal(t, "10/07/5784 A. M.", formatter.FormatSkeleton(date, "GyMd", time.UTC))
+
+	assert.EqualError(t, formatter.SetCalendar("chinese"), "unsupported calendar: chinese")
+	assert.Error(t, formatter.SetCalendar("islamic-tbla"))
+	assert.Equal(t, "hebrew", formatter.GetCalendar())
+}
+
+func Test_NewDateFormatterForTag(t *testing.T) {
+	formatter, err := golocales.NewDateFormatterForTag("fr-u-ca-japanese")
+	assert.NoError(t, err)
+	assert.Same(t, fr.GetLocale(), formatter.GetLocale())
+	assert.Equal(t, "japanese", formatter.GetCalendar())
+	assert.Equal(t, "20 mars 6 Reiwa", formatter.FormatDateTime(time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), "long", "", time.UTC))
+
+	// the unsupported calendar is ignored
+	formatter, err = golocales.NewDateFormatterForTag("fr-u-ca-chinese")
+	assert.NoError(t, err)
+	assert.Equal(t, "gregorian", formatter.GetCalendar())
+
+	_, err = golocales.NewDateFormatterForTag("xx-YY-u-ca-japanese")
+	assert.EqualError(t, err, "the locale of the tag xx-YY-u-ca-japanese is not available")
+}
@@ -97,6 +97,20 @@ func GetSymbol(currencyCode string, locale *dto.Locale) (symbol string, ok bool)
 	}
 }
 
+// GetNarrowSymbol returns the narrow symbol for a currency code, ie: "$"
+// for USD in fr, the symbol is used if the locale has no narrow symbol.
+func GetNarrowSymbol(currencyCode string, locale *dto.Locale) (symbol string, ok bool) {
+	if currencyCode == "" || !IsValid(currencyCode) {
+		return currencyCode, false
+	}
+
+	if symbol := locale.GetCurrencySymbol(currencyCode, "narrow"); symbol != "" {
+		return symbol, true
+	}
+
+	return currencyCode, true
+}
+
 // // getFormat returns the format for a locale.
 // func getFormat(locale Locale) currencyFormat {
 // 	var format currencyFormat
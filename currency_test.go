@@ -11,6 +11,7 @@ package golocales
 import (
 	"testing"
 
+	"github.com/rande/golocales/locales/fr"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -137,3 +138,29 @@ func TestGetDigits(t *testing.T) {
 // 		})
 // 	}
 // }
+
+func TestGetNarrowSymbol(t *testing.T) {
+	symbol, ok := GetSymbol("USD", fr.GetLocale())
+	assert.True(t, ok)
+	assert.Equal(t, "$US", symbol)
+
+	symbol, ok = GetNarrowSymbol("USD", fr.GetLocale())
+	assert.True(t, ok)
+	assert.Equal(t, "$", symbol)
+
+	// no narrow symbol, the symbol is used
+	symbol, ok = GetNarrowSymbol("CHF", fr.GetLocale())
+	assert.True(t, ok)
+	assert.Equal(t, "CHF", symbol)
+
+	_, ok = GetNarrowSymbol("usd", fr.GetLocale())
+	assert.False(t, ok)
+}
+
+func TestGetTerritoryName(t *testing.T) {
+	l := fr.GetLocale()
+
+	assert.Equal(t, "Andorre", l.GetTerritoryName("AD", ""))
+	assert.Equal(t, "Andorre", l.GetTerritoryName("AD", "short"))
+	assert.Equal(t, "", l.GetTerritoryName("XX", "short"))
+}
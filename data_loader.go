@@ -0,0 +1,151 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/rande/golocales/dto"
+	"github.com/rande/golocales/locales/root"
+)
+
+// DataLoader builds the locales from the data files generated with the
+// "-format data" option of the generator: one <name>.json file per locale.
+// The root locale is always the generated package. The files can be
+// embedded (golocales/locales/data) or read from a folder with os.DirFS.
+type DataLoader struct {
+	fsys    fs.FS
+	mutex   sync.Mutex
+	locales map[string]*dto.Locale
+}
+
+func NewDataLoader(fsys fs.FS) *DataLoader {
+	return &DataLoader{
+		fsys:    fsys,
+		locales: map[string]*dto.Locale{},
+	}
+}
+
+// Names returns the sorted names of the locales available in the data
+// files.
+func (d *DataLoader) Names() ([]string, error) {
+	files, err := fs.Glob(d.fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, file := range files {
+		names = append(names, strings.TrimSuffix(path.Base(file), ".json"))
+	}
+
+	return names, nil
+}
+
+// Load returns the locale with its parents, the locales are built once.
+func (d *DataLoader) Load(name string) (*dto.Locale, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.load(name)
+}
+
+func (d *DataLoader) load(name string) (*dto.Locale, error) {
+	if name == "root" {
+		return root.GetLocale(), nil
+	}
+
+	if locale, ok := d.locales[name]; ok {
+		return locale, nil
+	}
+
+	content, err := fs.ReadFile(d.fsys, name+".json")
+	if err != nil {
+		return nil, err
+	}
+
+	data := &dto.LocaleData{}
+	if err := json.Unmarshal(content, data); err != nil {
+		return nil, fmt.Errorf("unable to decode the locale %s: %w", name, err)
+	}
+
+	if data.Locale == nil {
+		return nil, fmt.Errorf("the data file of the locale %s has no locale", name)
+	}
+
+	locale := data.Locale
+	if locale.Parent, err = d.load(data.Parent); err != nil {
+		return nil, err
+	}
+
+	d.locales[name] = locale
+
+	return locale, nil
+}
+
+// Register adds the locales of the data files to the registry, a locale is
+// loaded the first time it is requested. The files are checked before
+// registering the locales: an error is returned if a file is not valid JSON
+// or if its parent is not available. A loading error is then returned by
+// dto.LoadRegisteredLocale.
+func (d *DataLoader) Register() error {
+	names, err := d.Names()
+	if err != nil {
+		return err
+	}
+
+	available := map[string]bool{"root": true}
+	for _, name := range names {
+		available[name] = true
+	}
+
+	for _, name := range names {
+		if err := d.check(name, available); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		name := name
+		dto.RegisterLocaleLoader(name, func() (*dto.Locale, error) {
+			return d.Load(name)
+		})
+	}
+
+	return nil
+}
+
+// check decodes the data file without building the locale.
+func (d *DataLoader) check(name string, available map[string]bool) error {
+	content, err := fs.ReadFile(d.fsys, name+".json")
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		Parent string
+		Locale json.RawMessage
+	}{}
+
+	if err := json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("unable to decode the locale %s: %w", name, err)
+	}
+
+	if len(data.Locale) == 0 || string(data.Locale) == "null" {
+		return fmt.Errorf("the data file of the locale %s has no locale", name)
+	}
+
+	if !available[data.Parent] {
+		return fmt.Errorf("the parent %s of the locale %s is not available", data.Parent, name)
+	}
+
+	return nil
+}
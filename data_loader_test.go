@@ -0,0 +1,213 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales_test
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/rande/golocales"
+	"github.com/rande/golocales/dto"
+	_ "github.com/rande/golocales/locales/all"
+	"github.com/rande/golocales/locales/fr"
+	"github.com/rande/golocales/locales/root"
+	"github.com/stretchr/testify/assert"
+)
+
+func getDataFS(t *testing.T) fstest.MapFS {
+	data, err := json.Marshal(&dto.LocaleData{Parent: "root", Locale: fr.GetLocale()})
+	assert.Nil(t, err)
+
+	return fstest.MapFS{
+		"fr.json":     {Data: data},
+		"broken.json": {Data: []byte("{")},
+	}
+}
+
+func Test_DataLoader_Load(t *testing.T) {
+	loader := golocales.NewDataLoader(getDataFS(t))
+
+	names, err := loader.Names()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"broken", "fr"}, names)
+
+	locale, err := loader.Load("fr")
+	assert.Nil(t, err)
+	assert.NotSame(t, fr.GetLocale(), locale)
+	assert.Same(t, root.GetLocale(), locale.Parent)
+
+	same, _ := loader.Load("fr")
+	assert.Same(t, locale, same)
+
+	_, err = loader.Load("broken")
+	assert.NotNil(t, err)
+
+	_, err = loader.Load("de")
+	assert.NotNil(t, err)
+
+	// the formatters are built from the patterns
+	tm := time.Date(2024, 2, 9, 13, 4, 5, 0, time.UTC)
+	paris, _ := time.LoadLocation("Europe/Paris")
+	for _, style := range []string{"full", "long", "medium", "short"} {
+		expected := golocales.NewDateFormatter(fr.GetLocale()).FormatDateTime(tm, style, style, paris)
+		assert.Equal(t, expected, golocales.NewDateFormatter(locale).FormatDateTime(tm, style, style, paris))
+	}
+
+	amount, err := golocales.NewCurrency("1234.56", "EUR")
+	assert.Nil(t, err)
+	assert.Equal(t, golocales.NewAmountFormatter(fr.GetLocale()).Format(amount), golocales.NewAmountFormatter(locale).Format(amount))
+}
+
+func Test_DataLoader_DayPeriods(t *testing.T) {
+	locale := &dto.Locale{
+		Name: "xx",
+		Calendars: map[string]*dto.Calendar{
+			"gregorian": {
+				System: "gregorian",
+				DayPeriods: map[string][]*dto.DayPeriod{
+					"p_format_wide_B": {
+						{Label: "midi", At: 1200},
+						{Label: "du matin", At: -1, From: 400, Before: 1200},
+						{Label: "de la nuit", At: -1, From: 2100, Before: 400},
+					},
+				},
+			},
+		},
+	}
+
+	data, err := json.Marshal(&dto.LocaleData{Parent: "root", Locale: locale})
+	assert.Nil(t, err)
+
+	loaded, err := golocales.NewDataLoader(fstest.MapFS{"xx.json": {Data: data}}).Load("xx")
+	assert.Nil(t, err)
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+
+	assert.Equal(t, "midi", golocales.FormatPattern(loaded, "BBBB", time.Date(2024, 2, 9, 12, 0, 0, 0, time.UTC), time.UTC))
+	assert.Equal(t, "du matin", golocales.FormatPattern(loaded, "BBBB", time.Date(2024, 2, 9, 9, 30, 0, 0, time.UTC), time.UTC))
+	assert.Equal(t, "de la nuit", golocales.FormatPattern(loaded, "BBBB", time.Date(2024, 2, 9, 1, 0, 0, 0, time.UTC), time.UTC))
+	assert.Equal(t, "de la nuit", golocales.FormatPattern(loaded, "BBBB", time.Date(2024, 2, 9, 22, 0, 0, 0, time.UTC), paris))
+}
+
+func Test_DataLoader_Register(t *testing.T) {
+	data, err := json.Marshal(&dto.LocaleData{Parent: "root", Locale: &dto.Locale{Name: "fr_XX", Language: "fr", Territory: "XX"}})
+	assert.Nil(t, err)
+
+	invalid := []byte(`{"Parent": "root", "Locale": {"Name": 1}}`)
+
+	loader := golocales.NewDataLoader(fstest.MapFS{"fr_XX.json": {Data: data}, "fr_YY.json": {Data: invalid}})
+	assert.Nil(t, loader.Register())
+
+	assert.Contains(t, golocales.Available(), "fr_XX")
+
+	// the locale is loaded on first use
+	locale := golocales.Get("fr-XX")
+	assert.Equal(t, "fr_XX", locale.Name)
+	assert.Same(t, root.GetLocale(), locale.Parent)
+	assert.Same(t, locale, golocales.Get("fr_XX"))
+
+	// the loading error is available from the registry
+	assert.Nil(t, golocales.Get("fr_YY"))
+	_, err = dto.LoadRegisteredLocale("fr_YY")
+	assert.NotNil(t, err)
+}
+
+func Test_DataLoader_Register_Invalid(t *testing.T) {
+	data, err := json.Marshal(&dto.LocaleData{Parent: "fr_ZZ", Locale: &dto.Locale{Name: "fr_WW"}})
+	assert.Nil(t, err)
+
+	assert.NotNil(t, golocales.NewDataLoader(fstest.MapFS{"fr_WW.json": {Data: data}}).Register())
+	assert.NotNil(t, golocales.NewDataLoader(fstest.MapFS{"fr_WW.json": {Data: []byte("{")}}).Register())
+	assert.NotNil(t, golocales.NewDataLoader(fstest.MapFS{"fr_WW.json": {Data: []byte(`{"Parent": "root"}`)}}).Register())
+
+	// nothing is registered if a file is invalid
+	assert.NotContains(t, golocales.Available(), "fr_WW")
+}
+
+// getLocalesFS writes the locales and their parents into data files.
+func getLocalesFS(t *testing.T, names []string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+
+	for _, name := range names {
+		for locale := golocales.Get(name); locale != nil && locale.Parent != nil; locale = locale.Parent {
+			data, err := json.Marshal(&dto.LocaleData{Parent: locale.Parent.Name, Locale: locale})
+			assert.Nil(t, err)
+
+			fsys[locale.Name+".json"] = &fstest.MapFile{Data: data}
+		}
+	}
+
+	return fsys
+}
+
+func Test_DataLoader_SameOutput(t *testing.T) {
+	names := []string{"fr", "fr_CA", "en", "en_NL", "hi", "de_CH", "sr_Latn", "zh_Hant_HK"}
+	loader := golocales.NewDataLoader(getLocalesFS(t, names))
+
+	newYork, _ := time.LoadLocation("America/New_York")
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+
+	times := []time.Time{
+		time.Date(2024, 1, 10, 0, 30, 0, 0, time.UTC),
+		time.Date(2024, 7, 14, 12, 0, 0, 0, time.UTC),
+		time.Date(2023, 12, 31, 19, 45, 30, 0, time.UTC),
+	}
+
+	styles := []string{"", "short", "medium", "long", "full"}
+
+	// the letters of the date field symbol table
+	fields := []string{
+		"G", "GGGG", "GGGGG", "y", "yy", "yyyy", "Y", "u", "U", "r", "Q", "QQQ", "QQQQ", "q", "qqqq",
+		"M", "MM", "MMM", "MMMM", "MMMMM", "L", "LLL", "LLLL", "w", "W", "d", "D", "F", "g",
+		"E", "EEEE", "EEEEE", "EEEEEE", "e", "eee", "c", "cccc", "a", "aaaa", "aaaaa", "b", "bbbb",
+		"B", "BBBB", "BBBBB", "h", "H", "K", "k", "m", "s", "S", "SSS", "A",
+		"z", "zzzz", "Z", "ZZZZ", "ZZZZZ", "O", "OOOO", "v", "vvvv", "V", "VV", "VVV", "VVVV",
+		"X", "XXX", "x", "xxx",
+	}
+
+	for _, name := range names {
+		expected := golocales.Get(name)
+
+		actual, err := loader.Load(name)
+		assert.Nil(t, err)
+
+		for _, location := range []*time.Location{newYork, kolkata} {
+			for _, tm := range times {
+				for _, field := range fields {
+					assert.Equal(t, golocales.FormatPattern(expected, field, tm, location), golocales.FormatPattern(actual, field, tm, location), "%s %s", name, field)
+				}
+			}
+		}
+
+		for _, calendar := range golocales.SupportedCalendars {
+			goFormatter := golocales.NewDateFormatter(expected)
+			assert.Nil(t, goFormatter.SetCalendar(calendar))
+
+			dataFormatter := golocales.NewDateFormatter(actual)
+			assert.Nil(t, dataFormatter.SetCalendar(calendar))
+
+			for _, tm := range times {
+				for _, dateStyle := range styles {
+					for _, timeStyle := range styles {
+						assert.Equal(t, goFormatter.FormatDateTime(tm, dateStyle, timeStyle, newYork), dataFormatter.FormatDateTime(tm, dateStyle, timeStyle, newYork), "%s %s", name, calendar)
+					}
+				}
+
+				for skeleton := range expected.GetCalendarSkeletons(calendar) {
+					assert.Equal(t, goFormatter.FormatSkeleton(tm, skeleton, newYork), dataFormatter.FormatSkeleton(tm, skeleton, newYork), "%s %s %s", name, calendar, skeleton)
+				}
+
+				for skeleton := range expected.GetCalendarIntervals(calendar) {
+					for _, end := range []time.Time{tm.Add(90 * time.Minute), tm.Add(10 * 24 * time.Hour), tm.Add(400 * 24 * time.Hour)} {
+						assert.Equal(t, goFormatter.FormatInterval(tm, end, skeleton, newYork), dataFormatter.FormatInterval(tm, end, skeleton, newYork), "%s %s %s", name, calendar, skeleton)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rande/golocales/dto"
+)
+
+// DateFormatter formats dates with the patterns defined by the locale.
+type DateFormatter struct {
+	locale   *dto.Locale
+	calendar string
+}
+
+// NewDateFormatter creates a new DateFormatter for the given locale, the
+// calendar is the one preferred in the locale's territory.
+func NewDateFormatter(locale *dto.Locale) *DateFormatter {
+	return &DateFormatter{
+		locale:   locale,
+		calendar: getLocaleCalendar(locale),
+	}
+}
+
+// NewDateFormatterForTag creates a new DateFormatter for the locale of the
+// tag, see Get. The calendar is the one requested by the -u-ca- keyword of
+// the tag: "th-TH-u-ca-japanese" uses the japanese calendar. Without the
+// keyword, or if the requested calendar is not supported, the calendar is
+// the one preferred in the locale's territory.
+func NewDateFormatterForTag(tag string) (*DateFormatter, error) {
+	locale := Get(tag)
+	if locale == nil {
+		return nil, fmt.Errorf("the locale of the tag %s is not available", tag)
+	}
+
+	f := NewDateFormatter(locale)
+	if calendar := GetCalendarFromTag(tag); calendar != "" {
+		f.calendar = calendar
+	}
+
+	return f, nil
+}
+
+// Locale returns the locale.
+func (f *DateFormatter) GetLocale() *dto.Locale {
+	return f.locale
+}
+
+// GetCalendar returns the calendar system used to format the dates.
+func (f *DateFormatter) GetCalendar() string {
+	return f.calendar
+}
+
+// SetCalendar changes the calendar system, the BCP 47 names are accepted:
+// gregory or the value from GetCalendarFromTag. An error is returned if
+// the calendar is not supported, the calendar is not changed.
+func (f *DateFormatter) SetCalendar(calendar string) error {
+	name := NormalizeCalendar(calendar)
+	if name == "" {
+		return fmt.Errorf("unsupported calendar: %s", calendar)
+	}
+
+	f.calendar = name
+
+	return nil
+}
+
+// FormatDateTime formats the time with the date and time formats of the
+// locale (short, medium, long or full), combined with the locale's glue
+// pattern: "{1}, {0}" or "{1} 'at' {0}". An empty style omits the part. A
+// nil location uses the location of the time.
+func (f *DateFormatter) FormatDateTime(t time.Time, dateStyle, timeStyle string, location *time.Location) string {
+	t = inLocation(t, location)
+
+	datePart := f.formatStyle("date_"+dateStyle, t)
+	if timeStyle == "" {
+		return datePart
+	}
+
+	timePart := f.formatStyle("time_"+timeStyle, t)
+	if dateStyle == "" {
+		return timePart
+	}
+
+	return combineDateTime(f.getDateTimeGlue(dateStyle), datePart, timePart)
+}
+
+// getDateTimeGlue returns the glue pattern for the date style, the atTime
+// variant is used for the long and full styles as the date is a single
+// day: "January 10, 2024 at 10:00".
+func (f *DateFormatter) getDateTimeGlue(dateStyle string) string {
+	if dateStyle == "long" || dateStyle == "full" {
+		if glue := getCalendarPattern(f.locale, f.calendar, "datetime_"+dateStyle+"_atTime"); glue != "" {
+			return glue
+		}
+	}
+
+	if glue := getCalendarPattern(f.locale, f.calendar, "datetime_"+dateStyle); glue != "" {
+		return glue
+	}
+
+	return "{1} {0}"
+}
+
+// formatStyle formats the time with the pattern of the date or time style.
+func (f *DateFormatter) formatStyle(name string, t time.Time) string {
+	pattern := getCalendarPattern(f.locale, f.calendar, name)
+	if pattern == "" {
+		return ""
+	}
+
+	return f.format(pattern, t)
+}
+
+// intervalFields is the list of fields used to find the greatest difference
+// between two dates, from the largest to the smallest one.
+var intervalFields = []rune{'G', 'y', 'M', 'd', 'a', 'H', 'm', 's'}
+
+// FormatInterval formats a date range, the fields shared by both dates are
+// only displayed once, according to the greatest different field:
+// "Jan 10 – 20, 2024" or "10:00 – 11:30 AM". The skeleton lists the fields
+// to display, ie: yMMMd or hm. Both dates are converted into the location,
+// a nil location uses the location of start.
+func (f *DateFormatter) FormatInterval(start, end time.Time, skeleton string, location *time.Location) string {
+	start = inLocation(start, location)
+	end = end.In(start.Location())
+
+	pattern := f.GetSkeletonPattern(skeleton)
+
+	field := greatestDifference(f.calendar, start, end)
+	if field == 0 || !skeletonHasField(skeleton, field) && isSmallerThanSkeleton(skeleton, field) {
+		// the dates are equal for the requested fields
+		return f.format(pattern, start)
+	}
+
+	if interval := f.getIntervalPattern(skeleton, field); interval != "" {
+		items := compileDatePattern(interval)
+		split := splitIntervalPattern(items)
+
+		return formatDatePattern(f.locale, f.calendar, items[:split], start) +
+			formatDatePattern(f.locale, f.calendar, items[split:], end)
+	}
+
+	fallback := getCalendarPattern(f.locale, f.calendar, "interval_fallback")
+	if fallback == "" {
+		fallback = "{0} – {1}"
+	}
+
+	return strings.NewReplacer(
+		"{0}", f.format(pattern, start),
+		"{1}", f.format(pattern, end),
+	).Replace(fallback)
+}
+
+func (f *DateFormatter) format(pattern string, t time.Time) string {
+	return formatDatePattern(f.locale, f.calendar, compileDatePattern(pattern), t)
+}
+
+// GetSkeletonPattern returns the localized pattern best matching the
+// skeleton, ie: "MMMd" is "MMM d" in English and "d MMM" in French.
+func (f *DateFormatter) GetSkeletonPattern(skeleton string) string {
+	return getSkeletonPattern(f.locale, f.calendar, skeleton)
+}
+
+// FormatSkeleton formats the time with the pattern best matching the
+// skeleton, the skeleton lists the fields to display, ie: yMMMd or Hm. A nil
+// location uses the location of the time.
+func (f *DateFormatter) FormatSkeleton(t time.Time, skeleton string, location *time.Location) string {
+	return f.format(f.GetSkeletonPattern(skeleton), inLocation(t, location))
+}
+
+// getIntervalPattern returns the interval pattern for the greatest different
+// field, the hour fields depend on the one used in the skeleton. If the
+// locale does not define the skeleton, the closest one is used with its
+// field lengths adjusted.
+func (f *DateFormatter) getIntervalPattern(value string, field rune) string {
+	skeletons := getSkeletons(f.locale, f.calendar)
+	requested := parseSkeleton(value, skeletons.hour)
+
+	best := bestSkeleton(requested, skeletons.intervalCandidates)
+	if best == nil || best.distance >= skeletonMissingField {
+		return ""
+	}
+
+	fields := []rune{field}
+
+	switch field {
+	case 'a':
+		// the day period changes, so the hour changes too
+		fields = append(fields, 'h', 'H')
+	case 'H':
+		fields = []rune{'H', 'h', 'k', 'K'}
+	case 's':
+		// seconds are not defined in the intervals, use the minutes
+		fields = append(fields, 'm')
+	case 'G':
+		fields = append(fields, 'y')
+	}
+
+	for _, c := range fields {
+		if pattern, ok := skeletons.intervals[best.value][string(c)]; ok {
+			if best.distance == 0 {
+				return pattern
+			}
+
+			return formatPatternItems(adjustPattern(parseDatePattern(pattern), requested, best.skeleton))
+		}
+	}
+
+	return ""
+}
+
+// greatestDifference returns the largest field having a different value in
+// the calendar, 0 if the dates are equal up to the second.
+func greatestDifference(calendar string, a, b time.Time) rune {
+	da, db := toCalendarDate(calendar, a), toCalendarDate(calendar, b)
+
+	for _, field := range intervalFields {
+		switch field {
+		case 'G':
+			if da.Era != db.Era {
+				return field
+			}
+		case 'y':
+			if da.Year != db.Year {
+				return field
+			}
+		case 'M':
+			if da.Month != db.Month {
+				return field
+			}
+		case 'd':
+			if da.Day != db.Day {
+				return field
+			}
+		case 'a':
+			if (a.Hour() < 12) != (b.Hour() < 12) {
+				return field
+			}
+		case 'H':
+			if a.Hour() != b.Hour() {
+				return field
+			}
+		case 'm':
+			if a.Minute() != b.Minute() {
+				return field
+			}
+		case 's':
+			if a.Second() != b.Second() {
+				return field
+			}
+		}
+	}
+
+	return 0
+}
+
+// normalizeField maps a pattern letter to the field used to compute the
+// interval difference, ie: L (stand-alone month) is M, h is H.
+func normalizeField(c rune) rune {
+	switch c {
+	case 'L':
+		return 'M'
+	case 'E', 'c', 'e':
+		return 'd'
+	case 'h', 'k', 'K':
+		return 'H'
+	case 'b', 'B':
+		return 'a'
+	}
+
+	return c
+}
+
+func skeletonHasField(skeleton string, field rune) bool {
+	for _, c := range skeleton {
+		if normalizeField(c) == field {
+			return true
+		}
+
+		// the 12 hours format implies a day period
+		if field == 'a' && (c == 'h' || c == 'K') {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSmallerThanSkeleton returns true if the field is smaller than all the
+// fields of the skeleton, ie: a different minute with a yMMMd skeleton.
+func isSmallerThanSkeleton(skeleton string, field rune) bool {
+	index := func(f rune) int {
+		for i, c := range intervalFields {
+			if c == f {
+				return i
+			}
+		}
+
+		return -1
+	}
+
+	smallest := -1
+	for _, c := range skeleton {
+		if i := index(normalizeField(c)); i > smallest {
+			smallest = i
+		}
+	}
+
+	return index(field) > smallest
+}
+
+// splitIntervalPattern returns the index of the first repeated field, the
+// items before are formatted with the start date, the others with the end date.
+func splitIntervalPattern(items []patternItem) int {
+	seen := map[rune]bool{}
+
+	for i, item := range items {
+		if item.Field == 0 {
+			continue
+		}
+
+		field := normalizeField(item.Field)
+		if item.Field == 'E' || item.Field == 'c' || item.Field == 'e' {
+			// the weekday is only a repeated field if it is the same letter
+			field = item.Field
+		}
+
+		if seen[field] {
+			return i
+		}
+
+		seen[field] = true
+	}
+
+	return len(items)
+}
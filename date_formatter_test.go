@@ -0,0 +1,124 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rande/golocales"
+	"github.com/rande/golocales/locales/fr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DateFormatter_FormatInterval(t *testing.T) {
+	start := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		end      time.Time
+		skeleton string
+		want     string
+	}{
+		{start.Add(10 * 24 * time.Hour), "yMMMd", "10–20 janv. 2024"},
+		{start.Add(40 * 24 * time.Hour), "yMMMd", "10 janv.\u2009–\u200919 févr. 2024"},
+		{start.Add(400 * 24 * time.Hour), "yMMMd", "10 janv. 2024\u2009–\u200913 févr. 2025"},
+		{start.Add(10 * 24 * time.Hour), "yMMMEd", "mer. 10\u2009–\u2009sam. 20 janv. 2024"},
+		{start.Add(90 * time.Minute), "Hm", "10:00\u2009–\u200911:30"},
+		// the time fields are not part of the skeleton
+		{start.Add(5 * time.Hour), "yMMMd", "10 janv. 2024"},
+	}
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+
+	formatter := golocales.NewDateFormatter(fr.GetLocale())
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			assert.Equal(t, tt.want, formatter.FormatInterval(start, tt.end, tt.skeleton, paris))
+		})
+	}
+
+	// a nil location uses the location of the start date
+	assert.Equal(t, "10:00\u2009–\u200911:30", formatter.FormatInterval(start.In(paris), start.Add(90*time.Minute), "Hm", nil))
+}
+
+func Test_DateFormatter_GetSkeletonPattern(t *testing.T) {
+	tests := []struct {
+		skeleton string
+		want     string
+	}{
+		{"MMMd", "d MMM"},
+		{"yMd", "dd/MM/y"},
+		// the field lengths are adjusted
+		{"MMMMEEEEd", "EEEE d MMMM"},
+		{"yMMMMEEEEd", "EEEE d MMMM y"},
+		// the hour cycle of the locale
+		{"jm", "HH:mm"},
+		// the date and the time are combined
+		{"yMMMdHm", "d MMM y, HH:mm"},
+	}
+
+	formatter := golocales.NewDateFormatter(fr.GetLocale())
+
+	for _, tt := range tests {
+		t.Run(tt.skeleton, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatter.GetSkeletonPattern(tt.skeleton))
+		})
+	}
+}
+
+func Test_DateFormatter_FormatSkeleton(t *testing.T) {
+	date := time.Date(2024, 3, 5, 9, 30, 0, 0, time.UTC)
+	paris, _ := time.LoadLocation("Europe/Paris")
+
+	formatter := golocales.NewDateFormatter(fr.GetLocale())
+
+	assert.Equal(t, "5 mars", formatter.FormatSkeleton(date, "MMMd", paris))
+	assert.Equal(t, "mardi 5 mars 2024", formatter.FormatSkeleton(date, "yMMMMEEEEd", paris))
+	assert.Equal(t, "10:30", formatter.FormatSkeleton(date, "Hm", paris))
+	// a nil location uses the location of the time
+	assert.Equal(t, "09:30", formatter.FormatSkeleton(date, "Hm", nil))
+}
+
+func Test_DateFormatter_FormatDateTime(t *testing.T) {
+	date := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	paris, _ := time.LoadLocation("Europe/Paris")
+
+	tests := []struct {
+		dateStyle string
+		timeStyle string
+		want      string
+	}{
+		{"short", "short", "10/01/2024 10:00"},
+		{"medium", "short", "10 janv. 2024, 10:00"},
+		// the atTime glue is used for the long and full styles
+		{"long", "short", "10 janvier 2024 à 10:00"},
+		{"full", "short", "mercredi 10 janvier 2024 à 10:00"},
+		{"medium", "", "10 janv. 2024"},
+		{"", "short", "10:00"},
+	}
+
+	formatter := golocales.NewDateFormatter(fr.GetLocale())
+
+	for _, tt := range tests {
+		t.Run(tt.dateStyle+"-"+tt.timeStyle, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatter.FormatDateTime(date, tt.dateStyle, tt.timeStyle, paris))
+		})
+	}
+
+	// a nil location uses the location of the time
+	assert.Equal(t, "10/01/2024 12:00", formatter.FormatDateTime(date.In(time.FixedZone("UTC+3", 3*3600)), "short", "short", nil))
+}
+
+func Test_DateFormatter_FormatSkeleton_QuarterAndEra(t *testing.T) {
+	date := time.Date(2024, 8, 5, 9, 30, 0, 0, time.UTC)
+
+	formatter := golocales.NewDateFormatter(fr.GetLocale())
+
+	assert.Equal(t, "T3 2024", formatter.FormatSkeleton(date, "yQQQ", time.UTC))
+	assert.Equal(t, "3e trimestre 2024", formatter.FormatSkeleton(date, "yQQQQ", time.UTC))
+	assert.Equal(t, "2024 ap. J.-C.", formatter.FormatSkeleton(date, "Gy", time.UTC))
+}
@@ -0,0 +1,698 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rande/golocales/dto"
+)
+
+// ParseError describes a value not matching the pattern, the offset is the
+// position in bytes of the invalid part of the value.
+type ParseError struct {
+	Value   string
+	Pattern string
+	Offset  int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parsing %q with pattern %q at offset %d: %s", e.Value, e.Pattern, e.Offset, e.Message)
+}
+
+type ParseOptions struct {
+	// Reference is the time used to resolve the values depending on the
+	// current date: a two-digit year is in the century starting 80 years
+	// before the reference, a missing era is the one of the reference, and
+	// the zone names are the ones of its year.
+	// Defaults to the current time.
+	Reference time.Time
+}
+
+func CreateParseOptions() *ParseOptions {
+	return &ParseOptions{
+		Reference: time.Now(),
+	}
+}
+
+// ParsePattern parses the value with a LDML pattern, ie: "d MMMM y", the
+// month and day names, the day periods and the digits are the ones of the
+// locale. The text fields are case insensitive and the abbreviated, wide
+// and stand-alone forms are accepted. As time.Parse, the missing fields
+// are the ones of January 1, year 0, and the value is in loc if it does not
+// contain an offset. A nil loc is UTC. As FormatPattern, the calendar is
+// the one preferred in the locale's territory.
+func ParsePattern(locale *dto.Locale, pattern, value string, loc *time.Location, options ...*ParseOptions) (time.Time, error) {
+	return parseDate(locale, getLocaleCalendar(locale), pattern, value, loc, options...)
+}
+
+// Parse parses the value with a format of the locale: date_short,
+// time_medium or datetime_long, as returned by FormatDateTime. The value is
+// parsed with the calendar of the formatter.
+func (f *DateFormatter) Parse(value, style string, loc *time.Location, options ...*ParseOptions) (time.Time, error) {
+	pattern := f.getParsePattern(style)
+	if pattern == "" {
+		return time.Time{}, &ParseError{Value: value, Pattern: style, Message: "unknown style"}
+	}
+
+	return parseDate(f.locale, f.calendar, pattern, value, loc, options...)
+}
+
+func parseDate(locale *dto.Locale, calendar, pattern, value string, loc *time.Location, options ...*ParseOptions) (time.Time, error) {
+	parsingOptions := CreateParseOptions()
+	if len(options) > 0 && !options[0].Reference.IsZero() {
+		parsingOptions.Reference = options[0].Reference
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	p := &dateParser{
+		locale:    locale,
+		calendar:  calendar,
+		pattern:   pattern,
+		value:     value,
+		loc:       loc,
+		reference: parsingOptions.Reference.In(loc),
+		digits:    []rune(localDigits[locale.Number.DefaultNumberSystem]),
+	}
+
+	return p.parse(compileDatePattern(pattern))
+}
+
+// getParsePattern returns the pattern of the style, the datetime styles
+// combine the date and time patterns with the glue used by FormatDateTime.
+func (f *DateFormatter) getParsePattern(style string) string {
+	kind, length, _ := strings.Cut(style, "_")
+
+	switch kind {
+	case "date", "time":
+		return getCalendarPattern(f.locale, f.calendar, style)
+	case "datetime":
+		datePattern := getCalendarPattern(f.locale, f.calendar, "date_"+length)
+		timePattern := getCalendarPattern(f.locale, f.calendar, "time_"+length)
+		if datePattern == "" || timePattern == "" {
+			return ""
+		}
+
+		// the glue is a pattern, so its quoted literals are kept
+		return strings.NewReplacer("{0}", timePattern, "{1}", datePattern).Replace(f.getDateTimeGlue(length))
+	}
+
+	return ""
+}
+
+// dateParser holds the fields read from the value.
+type dateParser struct {
+	locale    *dto.Locale
+	calendar  string
+	pattern   string
+	value     string
+	loc       *time.Location
+	reference time.Time
+	digits    []rune
+	pos       int
+
+	// the date in the calendar, the month is the position of the month or
+	// the index of its label
+	date                                calendarDate
+	hour, minute, second, nanosecond    int
+	hasEra, hasYear, extendedYear       bool
+	twelveHours, hasOffset              bool
+	offset                              int
+	periodHours                         []int // the hours matching the day period
+	eraOffset, monthOffset, dayOffset   int
+	hourOffset, minuteOffset, secOffset int
+}
+
+func (p *dateParser) error(offset int, format string, args ...any) error {
+	return &ParseError{Value: p.value, Pattern: p.pattern, Offset: offset, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *dateParser) rest() string {
+	return p.value[p.pos:]
+}
+
+func (p *dateParser) parse(items []patternItem) (time.Time, error) {
+	p.date = calendarDate{Month: 1, Day: 1}
+
+	for i, item := range items {
+		var err error
+		if item.Field == 0 {
+			err = p.parseLiteral(item.Literal)
+		} else {
+			err = p.parseField(item, adjacentDigits(items[i+1:]))
+		}
+
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	if p.pos < len(p.value) {
+		return time.Time{}, p.error(p.pos, "unexpected text %q", p.rest())
+	}
+
+	return p.build()
+}
+
+// adjacentDigits returns the number of digits used by the numeric fields
+// following a field without a literal between them: 4 for the MMdd part of
+// yMMdd. A numeric field followed by another one has a fixed length.
+func adjacentDigits(items []patternItem) int {
+	digits := 0
+	for _, item := range items {
+		if item.Field == 0 || isTextField(item) {
+			break
+		}
+
+		digits += item.Count
+	}
+
+	return digits
+}
+
+// parseLiteral matches the literal, the spaces of the pattern match any
+// number of spaces, including the non breaking ones.
+func (p *dateParser) parseLiteral(literal string) error {
+	for _, c := range literal {
+		if unicode.IsSpace(c) {
+			p.skipSpaces()
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(p.rest())
+		if size == 0 || unicode.ToLower(r) != unicode.ToLower(c) {
+			return p.error(p.pos, "expected %q", literal)
+		}
+
+		p.pos += size
+	}
+
+	return nil
+}
+
+func (p *dateParser) skipSpaces() {
+	for {
+		r, size := utf8.DecodeRuneInString(p.rest())
+		if size == 0 || !unicode.IsSpace(r) {
+			return
+		}
+
+		p.pos += size
+	}
+}
+
+// digit returns the value of an ascii or a localized digit.
+func (p *dateParser) digit(r rune) int {
+	if r >= '0' && r <= '9' {
+		return int(r - '0')
+	}
+
+	for i, d := range p.digits {
+		if d == r {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// countDigits returns the number of digits at the current position.
+func (p *dateParser) countDigits() int {
+	count := 0
+	for _, r := range p.rest() {
+		if p.digit(r) < 0 {
+			break
+		}
+
+		count++
+	}
+
+	return count
+}
+
+// parseNumber reads between min and max digits.
+func (p *dateParser) parseNumber(min, max int) (int, int, error) {
+	start := p.pos
+	value, count := 0, 0
+
+	for count < max {
+		r, size := utf8.DecodeRuneInString(p.rest())
+
+		d := p.digit(r)
+		if size == 0 || d < 0 {
+			break
+		}
+
+		value = value*10 + d
+		count++
+		p.pos += size
+	}
+
+	if count < min {
+		return 0, 0, p.error(start, "expected a number")
+	}
+
+	return value, count, nil
+}
+
+// parseLabel matches the longest label, the abbreviated labels also match
+// without their final dot: "janv" is "janv.".
+func (p *dateParser) parseLabel(labels map[string]int) (int, bool) {
+	rest := p.rest()
+
+	best, length := 0, 0
+	for label, index := range labels {
+		for _, candidate := range []string{label, strings.TrimSuffix(label, ".")} {
+			if candidate == "" || len(candidate) <= length || len(rest) < len(candidate) {
+				continue
+			}
+
+			if strings.EqualFold(rest[:len(candidate)], candidate) {
+				best, length = index, len(candidate)
+			}
+		}
+	}
+
+	p.pos += length
+
+	return best, length > 0
+}
+
+// getLabels returns the labels of all the widths and contexts of the
+// calendar, with their index. The leap month labels are included: Adar II.
+func (p *dateParser) getLabels(prefix string) map[string]int {
+	labels := map[string]int{}
+
+	for _, context := range []string{"format", "stand-alone"} {
+		for _, width := range []string{"wide", "abbreviated"} {
+			for _, suffix := range []string{"", "_leap"} {
+				for i, label := range p.getCalendarLabels(fmt.Sprintf("%s_%s_%s%s", prefix, context, width, suffix)) {
+					if _, ok := labels[label]; !ok && label != "" {
+						labels[label] = i
+					}
+				}
+			}
+		}
+	}
+
+	return labels
+}
+
+// getCalendarLabels returns the labels of the calendar, the calendar can
+// use the gregorian labels, ie: the week days.
+func (p *dateParser) getCalendarLabels(name string) []string {
+	if labels := p.locale.GetCalendarLabels(getLabelCalendar(p.calendar), name); len(labels) > 0 {
+		return labels
+	}
+
+	if strings.HasSuffix(name, "_leap") {
+		return nil
+	}
+
+	return p.locale.GetCalendarLabels(DefaultCalendar, name)
+}
+
+// getPeriodLabels returns the day period labels with the hours they cover,
+// the labels are computed by the formatters.
+func (p *dateParser) getPeriodLabels(item patternItem) map[string][]int {
+	labels := map[string][]int{}
+
+	for hour := 0; hour < 24; hour++ {
+		t := time.Date(2000, time.January, 1, hour, 0, 0, 0, time.UTC)
+
+		for _, count := range []int{1, 4, 5} {
+			label := formatDateField(p.locale, DefaultCalendar, patternItem{Field: item.Field, Count: count}, t)
+			labels[label] = append(labels[label], hour)
+		}
+	}
+
+	return labels
+}
+
+// parseField reads a field, adjacent is the number of digits of the numeric
+// fields following the field: the fixed width fields (MM, dd, HH) use their
+// length, and the year uses the remaining digits.
+func (p *dateParser) parseField(item patternItem, adjacent int) error {
+	start := p.pos
+
+	// the minimum and maximum number of digits of a numeric field
+	length := func(max int) (int, int) {
+		if adjacent > 0 {
+			return item.Count, item.Count
+		}
+
+		return 1, max
+	}
+
+	var err error
+	switch item.Field {
+	case 'G':
+		// a locale can only define some eras, the other ones are read from
+		// the parents like the formatter does
+		labels := map[string]int{}
+		for _, width := range []string{"wide", "abbreviated", "narrow"} {
+			for locale := p.locale; locale != nil; locale = locale.Parent {
+				for i, label := range locale.GetCalendarLabels(getLabelCalendar(p.calendar), "era_"+width) {
+					if _, ok := labels[label]; label != "" && !ok {
+						labels[label] = i
+					}
+				}
+			}
+		}
+
+		era, ok := p.parseLabel(labels)
+		if !ok {
+			return p.error(start, "expected an era")
+		}
+
+		p.date.Era, p.hasEra, p.eraOffset = era, true, start
+	case 'y', 'u':
+		var count int
+		min, max := 1, 9
+		if adjacent > 0 {
+			min, max = 1, p.countDigits()-adjacent
+		}
+
+		if item.Count == 2 {
+			min, max = 2, 2
+		}
+
+		if p.date.Year, count, err = p.parseNumber(min, max); err != nil {
+			return err
+		}
+
+		if item.Count == 2 && count == 2 {
+			p.date.Year = resolveTwoDigitYear(p.date.Year, p.referenceYear(item.Field))
+		}
+
+		p.hasYear, p.extendedYear = true, item.Field == 'u'
+	case 'M', 'L':
+		p.monthOffset = start
+		if item.Count <= 2 {
+			min, max := length(2)
+			p.date.Month, _, err = p.parseNumber(min, max)
+
+			return err
+		}
+
+		month, ok := p.parseLabel(p.getLabels("m"))
+		if !ok {
+			return p.error(start, "expected a month")
+		}
+
+		p.date.Month, p.date.MonthLabel = 0, month+1
+	case 'd':
+		p.dayOffset = start
+		min, max := length(2)
+		p.date.Day, _, err = p.parseNumber(min, max)
+	case 'E', 'c', 'e':
+		if item.Field != 'E' && item.Count <= 2 {
+			_, _, err = p.parseNumber(1, 1)
+
+			return err
+		}
+
+		// the week day is implied by the date
+		if _, ok := p.parseLabel(p.getLabels("d")); !ok {
+			return p.error(start, "expected a week day")
+		}
+	case 'a', 'b', 'B':
+		labels := map[string]int{}
+		periods := p.getPeriodLabels(item)
+
+		index := 0
+		names := make([]string, 0, len(periods))
+		for label := range periods {
+			labels[label] = index
+			names = append(names, label)
+			index++
+		}
+
+		i, ok := p.parseLabel(labels)
+		if !ok {
+			return p.error(start, "expected a day period")
+		}
+
+		p.periodHours = periods[names[i]]
+	case 'h', 'H', 'K', 'k':
+		p.hourOffset = start
+		min, max := length(2)
+		if p.hour, _, err = p.parseNumber(min, max); err != nil {
+			return err
+		}
+
+		switch item.Field {
+		case 'h':
+			if p.hour < 1 || p.hour > 12 {
+				return p.error(start, "hour out of range")
+			}
+
+			p.hour %= 12
+			p.twelveHours = true
+		case 'K':
+			if p.hour > 11 {
+				return p.error(start, "hour out of range")
+			}
+
+			p.twelveHours = true
+		case 'k':
+			if p.hour < 1 || p.hour > 24 {
+				return p.error(start, "hour out of range")
+			}
+
+			p.hour %= 24
+		}
+	case 'm':
+		p.minuteOffset = start
+		min, max := length(2)
+		p.minute, _, err = p.parseNumber(min, max)
+	case 's':
+		p.secOffset = start
+		min, max := length(2)
+		p.second, _, err = p.parseNumber(min, max)
+	case 'S':
+		var value, count int
+		min, max := length(9)
+		if value, count, err = p.parseNumber(min, max); err != nil {
+			return err
+		}
+
+		for ; count < 9; count++ {
+			value *= 10
+		}
+
+		p.nanosecond = value
+	case 'Z', 'X', 'x', 'O':
+		return p.parseOffset(item)
+	case 'z', 'v', 'V':
+		return p.parseZoneName(item)
+	default:
+		return p.error(start, "unsupported field %q", strings.Repeat(string(item.Field), item.Count))
+	}
+
+	return err
+}
+
+// parseOffset reads an ISO 8601 offset (Z, +01, +0100, +01:00) or the
+// localized GMT format: "UTC+1" or "GMT-08:00".
+func (p *dateParser) parseOffset(item patternItem) error {
+	start := p.pos
+
+	if item.Field == 'O' || item.Field == 'Z' && item.Count == 4 {
+		format := p.locale.GetTimeFormat()
+
+		zero := format.GmtZero
+		if zero == "" {
+			zero = "GMT"
+		}
+
+		prefix, _, _ := strings.Cut(format.Gmt, "{0}")
+		if prefix == "" {
+			prefix = "GMT"
+		}
+
+		rest := p.rest()
+		if len(rest) < len(prefix) || !strings.EqualFold(rest[:len(prefix)], prefix) {
+			return p.error(start, "expected %q", prefix)
+		}
+
+		p.pos += len(prefix)
+
+		if r, _ := utf8.DecodeRuneInString(p.rest()); r != '+' && r != '-' && r != '−' {
+			if prefix != zero {
+				p.pos = start
+
+				return p.parseLiteral(zero)
+			}
+
+			p.hasOffset, p.offset = true, 0
+
+			return nil
+		}
+	} else if r, size := utf8.DecodeRuneInString(p.rest()); r == 'Z' && item.Field != 'x' {
+		p.pos += size
+		p.hasOffset, p.offset = true, 0
+
+		return nil
+	}
+
+	sign := 1
+	switch r, size := utf8.DecodeRuneInString(p.rest()); r {
+	case '+':
+		p.pos += size
+	case '-', '−':
+		sign = -1
+		p.pos += size
+	default:
+		return p.error(p.pos, "expected an offset")
+	}
+
+	hours, count, err := p.parseNumber(1, 2)
+	if err != nil {
+		return err
+	}
+
+	minutes := 0
+	if strings.HasPrefix(p.rest(), ":") {
+		p.pos++
+		if minutes, _, err = p.parseNumber(2, 2); err != nil {
+			return err
+		}
+	} else if count == 2 {
+		// the minutes are optional: +01 or +0100
+		if value, n, _ := p.parseNumber(0, 2); n == 2 {
+			minutes = value
+		}
+	}
+
+	if hours > 23 || minutes > 59 {
+		return p.error(start, "offset out of range")
+	}
+
+	p.hasOffset, p.offset = true, sign*(hours*3600+minutes*60)
+
+	return nil
+}
+
+// parseZoneName matches the names of the location, the standard and the
+// daylight names are the ones of the current year. The other zones must
+// use the localized GMT format.
+func (p *dateParser) parseZoneName(item patternItem) error {
+	names := map[string]int{}
+
+	year := p.reference.Year()
+	for _, month := range []time.Month{time.January, time.July} {
+		t := time.Date(year, month, 1, 0, 0, 0, 0, p.loc)
+
+		for _, count := range []int{1, 4} {
+			names[formatZone(p.locale, patternItem{Field: item.Field, Count: count}, t)] = 0
+		}
+	}
+
+	if _, ok := p.parseLabel(names); ok {
+		return nil
+	}
+
+	return p.parseOffset(patternItem{Field: 'O', Count: 4})
+}
+
+// referenceYear returns the year of the reference in the calendar, the
+// extended year of the japanese and roc calendars is the gregorian year.
+func (p *dateParser) referenceYear(field rune) int {
+	if field == 'u' && (p.calendar == "japanese" || p.calendar == "roc") {
+		return p.reference.Year()
+	}
+
+	return toCalendarDate(p.calendar, p.reference).Year
+}
+
+// resolveTwoDigitYear returns the year in the century starting 80 years
+// before the current year.
+func resolveTwoDigitYear(year, now int) int {
+	start := now - 80
+	year += start - start%100
+
+	if year < start {
+		year += 100
+	}
+
+	return year
+}
+
+// build validates the fields and creates the time.
+func (p *dateParser) build() (time.Time, error) {
+	calendar, date := p.calendar, p.date
+	if !p.hasEra {
+		date.Era = toCalendarDate(calendar, p.reference).Era
+	}
+
+	// without a year, the date is in the gregorian year 0 as with
+	// time.Parse, the extended year of the japanese and roc calendars is
+	// the gregorian year
+	if !p.hasYear || p.extendedYear && (calendar == "japanese" || calendar == "roc") {
+		calendar, date.Era = DefaultCalendar, 1
+	}
+
+	months := 12
+	if calendar == "hebrew" {
+		months = 13
+	}
+
+	if date.Month < 0 || date.Month > months || date.Month == 0 && date.MonthLabel == 0 {
+		return time.Time{}, p.error(p.monthOffset, "month out of range")
+	}
+
+	if calendar == "japanese" && !slices.ContainsFunc(japaneseEras, func(e japaneseEra) bool { return e.Type == date.Era }) {
+		return time.Time{}, p.error(p.eraOffset, "unsupported era")
+	}
+
+	day, ok := fromCalendarDate(calendar, date)
+	if !ok {
+		return time.Time{}, p.error(p.dayOffset, "day out of range")
+	}
+
+	hour := p.hour
+	if p.periodHours != nil {
+		if !p.twelveHours {
+			return time.Time{}, p.error(p.hourOffset, "the day period requires a 12 hours field")
+		}
+
+		// the day period selects the morning or the afternoon hour
+		if !slices.Contains(p.periodHours, hour) {
+			hour += 12
+		}
+	}
+
+	if hour > 23 {
+		return time.Time{}, p.error(p.hourOffset, "hour out of range")
+	}
+
+	if p.minute > 59 {
+		return time.Time{}, p.error(p.minuteOffset, "minute out of range")
+	}
+
+	if p.second > 59 {
+		return time.Time{}, p.error(p.secOffset, "second out of range")
+	}
+
+	loc := p.loc
+	if p.hasOffset {
+		loc = time.FixedZone("", p.offset)
+	}
+
+	t := time.Date(day.Year(), day.Month(), day.Day(), hour, p.minute, p.second, p.nanosecond, loc)
+
+	return t.In(p.loc), nil
+}
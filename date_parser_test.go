@@ -0,0 +1,158 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rande/golocales"
+	"github.com/rande/golocales/locales/fr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParsePattern(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+
+	cases := []struct {
+		pattern  string
+		value    string
+		expected time.Time
+	}{
+		{"d MMMM y", "10 janvier 2024", time.Date(2024, 1, 10, 0, 0, 0, 0, paris)},
+		// case insensitive, abbreviated and stand-alone forms
+		{"d MMMM y", "10 JANV. 2024", time.Date(2024, 1, 10, 0, 0, 0, 0, paris)},
+		{"d MMM y", "10 janv 2024", time.Date(2024, 1, 10, 0, 0, 0, 0, paris)},
+		{"LLLL y", "février 2024", time.Date(2024, 2, 1, 0, 0, 0, 0, paris)},
+		{"EEEE d MMMM y", "mercredi 10 janvier 2024", time.Date(2024, 1, 10, 0, 0, 0, 0, paris)},
+		// the spaces of the pattern match any spaces
+		{"d MMM y HH:mm", "10 janv.  2024 14:05", time.Date(2024, 1, 10, 14, 5, 0, 0, paris)},
+		{"dd/MM/yy", "10/01/24", time.Date(2024, 1, 10, 0, 0, 0, 0, paris)},
+		{"HHmmss", "140509", time.Date(0, 1, 1, 14, 5, 9, 0, paris)},
+		// the year takes the digits not used by the fixed width fields
+		{"yMMdd", "20240110", time.Date(2024, 1, 10, 0, 0, 0, 0, paris)},
+		{"yMMddHHmm", "202401101405", time.Date(2024, 1, 10, 14, 5, 0, 0, paris)},
+		{"HH:mm:ss.SSS", "14:05:09.123", time.Date(0, 1, 1, 14, 5, 9, 123000000, paris)},
+		{"h:mm a", "2:05 PM", time.Date(0, 1, 1, 14, 5, 0, 0, paris)},
+		{"h:mm a", "12:05 AM", time.Date(0, 1, 1, 0, 5, 0, 0, paris)},
+		{"y G", "44 av. J.-C.", time.Date(-43, 1, 1, 0, 0, 0, 0, paris)},
+		{"yyyy-MM-dd'T'HH:mmXXX", "2024-01-10T14:05+02:00", time.Date(2024, 1, 10, 13, 5, 0, 0, paris)},
+		{"yyyy-MM-dd'T'HH:mmX", "2024-01-10T14:05Z", time.Date(2024, 1, 10, 15, 5, 0, 0, paris)},
+		{"HH:mm O", "14:05 UTC−5", time.Date(0, 1, 1, 14, 5, 0, 0, time.FixedZone("", -5*3600)).In(paris)},
+	}
+
+	for _, c := range cases {
+		value, err := golocales.ParsePattern(fr.GetLocale(), c.pattern, c.value, paris)
+
+		assert.Nil(t, err, c.value)
+		assert.True(t, c.expected.Equal(value), "%s: %s", c.value, value)
+		assert.Equal(t, paris, value.Location())
+	}
+}
+
+func Test_ParsePattern_Error(t *testing.T) {
+	cases := []struct {
+		pattern string
+		value   string
+		offset  int
+		message string
+	}{
+		{"d MMMM y", "10 foo 2024", 3, "expected a month"},
+		{"d MMMM y", "10 janvier", 10, "expected a number"},
+		{"dd/MM/y", "10-01-2024", 2, "expected \"/\""},
+		{"dd/MM/y", "31/02/2024", 0, "day out of range"},
+		{"dd/MM/y", "10/13/2024", 3, "month out of range"},
+		{"HH:mm", "14:05 foo", 5, "unexpected text \" foo\""},
+		{"HH:mm", "14:75", 3, "minute out of range"},
+	}
+
+	for _, c := range cases {
+		_, err := golocales.ParsePattern(fr.GetLocale(), c.pattern, c.value, nil)
+
+		if assert.IsType(t, &golocales.ParseError{}, err, c.value) {
+			assert.Equal(t, c.offset, err.(*golocales.ParseError).Offset, c.value)
+			assert.Equal(t, c.message, err.(*golocales.ParseError).Message, c.value)
+		}
+	}
+}
+
+func Test_ParsePattern_Reference(t *testing.T) {
+	options := golocales.CreateParseOptions()
+	options.Reference = time.Date(1950, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// the century starts 80 years before the reference
+	value, err := golocales.ParsePattern(fr.GetLocale(), "dd/MM/yy", "10/01/24", time.UTC, options)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(1924, 1, 10, 0, 0, 0, 0, time.UTC), value)
+
+	value, err = golocales.ParsePattern(fr.GetLocale(), "dd/MM/yy", "10/01/90", time.UTC, options)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(1890, 1, 10, 0, 0, 0, 0, time.UTC), value)
+
+	// the zero value is the current time
+	value, err = golocales.ParsePattern(fr.GetLocale(), "dd/MM/yy", "10/01/24", time.UTC, &golocales.ParseOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2024, value.Year())
+}
+
+func Test_DateFormatter_Parse_Calendars(t *testing.T) {
+	formatter := golocales.NewDateFormatter(fr.GetLocale())
+
+	dates := []time.Time{
+		time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 20, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 10, 3, 10, 0, 0, 0, time.UTC),
+		time.Date(1989, 1, 7, 10, 0, 0, 0, time.UTC),
+	}
+
+	// the formatted dates are parsed with the calendar of the formatter
+	for _, calendar := range golocales.SupportedCalendars {
+		assert.NoError(t, formatter.SetCalendar(calendar))
+
+		for _, date := range dates {
+			for _, style := range []string{"short", "medium", "long", "full"} {
+				value, err := formatter.Parse(formatter.FormatDateTime(date, style, style, time.UTC), "datetime_"+style, time.UTC)
+				if assert.Nil(t, err, "%s %s %s", calendar, style, date) {
+					assert.Equal(t, date, value, "%s %s", calendar, style)
+				}
+			}
+		}
+	}
+
+	assert.NoError(t, formatter.SetCalendar("persian"))
+
+	_, err := formatter.Parse("31 esfand 1402", "date_long", time.UTC)
+	assert.Error(t, err)
+}
+
+func Test_DateFormatter_Parse(t *testing.T) {
+	formatter := golocales.NewDateFormatter(fr.GetLocale())
+
+	date := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+
+	for _, style := range []string{"short", "medium", "long", "full"} {
+		value, err := formatter.Parse(formatter.FormatDateTime(date, style, "", time.UTC), "date_"+style, time.UTC)
+		assert.Nil(t, err, style)
+		assert.Equal(t, time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), value, style)
+
+		value, err = formatter.Parse(formatter.FormatDateTime(date, style, style, time.UTC), "datetime_"+style, time.UTC)
+		assert.Nil(t, err, style)
+		assert.Equal(t, date, value, style)
+	}
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+
+	value, err := formatter.Parse("10:00:00 heure d’été d’Europe centrale", "time_full", paris)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, value.Hour())
+
+	value, err = formatter.Parse("10:00:00 UTC+1", "time_long", paris)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, value.Hour())
+
+	_, err = formatter.Parse("10:00", "foo", time.UTC)
+	assert.Error(t, err)
+}
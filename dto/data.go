@@ -0,0 +1,13 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package dto
+
+// LocaleData is the content of a locale data file, the parent is stored by
+// name as it is loaded from another file (or from the generated package).
+type LocaleData struct {
+	Parent string
+	Locale *Locale
+}
@@ -0,0 +1,124 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package dto
+
+import (
+	"sort"
+	"sync"
+)
+
+// LocaleLoader builds a locale on first use, ie: from a data file.
+type LocaleLoader func() (*Locale, error)
+
+// registry holds the locales of the imported packages, the registry lives
+// in this package as the generated packages can not import golocales.
+var registry = struct {
+	sync.RWMutex
+	locales map[string]*Locale
+	loaders map[string]LocaleLoader
+	// the supplemental data of the root locale package
+	supplemental *Supplemental
+}{locales: map[string]*Locale{}, loaders: map[string]LocaleLoader{}}
+
+// RegisterLocale makes the locale available by its name, it is called by
+// the init function of the generated locale packages.
+func RegisterLocale(locale *Locale) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.locales[locale.Name] = locale
+}
+
+// RegisterLocaleLoader makes the locale available by its name, the loader
+// is only called the first time the locale is requested. A locale already
+// registered is kept.
+func RegisterLocaleLoader(name string, loader LocaleLoader) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.loaders[name] = loader
+}
+
+// RegisterSupplemental sets the data shared by all the locales, it is
+// called by the init function of the generated root locale package.
+func RegisterSupplemental(supplemental *Supplemental) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.supplemental = supplemental
+}
+
+// GetSupplemental returns the data shared by all the locales, or nil if the
+// root locale package is not imported. The methods of Supplemental accept a
+// nil receiver.
+func GetSupplemental() *Supplemental {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return registry.supplemental
+}
+
+// GetRegisteredLocale returns the locale registered with the CLDR name,
+// ie: fr_CA, or nil if the locale package is not imported or if the locale
+// can not be loaded, see LoadRegisteredLocale.
+func GetRegisteredLocale(name string) *Locale {
+	locale, _ := LoadRegisteredLocale(name)
+
+	return locale
+}
+
+// LoadRegisteredLocale returns the locale registered with the CLDR name, the
+// error of the loader is returned. The locale is nil without an error if
+// the name is not registered.
+func LoadRegisteredLocale(name string) (*Locale, error) {
+	registry.RLock()
+	locale, loader := registry.locales[name], registry.loaders[name]
+	registry.RUnlock()
+
+	if locale != nil || loader == nil {
+		return locale, nil
+	}
+
+	// the loader is called without the lock, as it can request the parent
+	// locales
+	locale, err := loader()
+	if err != nil {
+		return nil, err
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if registered, ok := registry.locales[name]; ok {
+		return registered, nil
+	}
+
+	registry.locales[name] = locale
+
+	return locale, nil
+}
+
+// GetRegisteredLocaleNames returns the sorted names of the registered
+// locales, the locales not loaded yet are included.
+func GetRegisteredLocaleNames() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	names := make([]string, 0, len(registry.locales)+len(registry.loaders))
+	for name := range registry.locales {
+		names = append(names, name)
+	}
+
+	for name := range registry.loaders {
+		if _, ok := registry.locales[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
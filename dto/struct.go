@@ -11,6 +11,8 @@ type Territory struct {
 	Name    string
 	Numeric string
 	Alpha3  string
+	// alt => name, ie: short => US
+	Alts map[string]string
 }
 
 type Currency struct {
@@ -21,14 +23,48 @@ type Currency struct {
 	CashDigits   uint8
 	CashRounding uint8
 	Numeric      string
+	// alt => symbol, ie: narrow => $
+	Alts map[string]string
 }
 
-type CalendarFormatter func(t time.Time, timeZone string) string
-
 type Calendar struct {
-	System     string
-	Labels     map[string][]string
-	Formatters map[string]CalendarFormatter
+	System    string
+	Labels    map[string][]string
+	Patterns  map[string]string
+	Skeletons map[string]string
+	Intervals map[string]map[string]string
+	// pattern key => day periods, ie: p_format_wide_b for the "bbbb" field,
+	// the periods are only loaded for the gregorian calendar
+	DayPeriods map[string][]*DayPeriod `json:",omitempty"`
+}
+
+// DayPeriod is a day period rule with its label, the times are hhmm values:
+// noon is At 1200, morning1 is From 600 Before 1200. At is -1 for a range.
+type DayPeriod struct {
+	Label  string
+	At     int
+	From   int
+	Before int
+}
+
+// Contains returns true if the hhmm time is in the period, a range ending
+// before its start crosses midnight: night1 is From 2100 Before 600.
+func (p *DayPeriod) Contains(hhmm int) bool {
+	switch {
+	case p.At != -1:
+		return hhmm == p.At
+	case p.Before > p.From:
+		return hhmm >= p.From && hhmm < p.Before
+	}
+
+	return hhmm >= p.From || hhmm < p.Before
+}
+
+type Field struct {
+	DisplayName string
+	Relative    map[string]string
+	Future      map[string]string
+	Past        map[string]string
 }
 
 type Number struct {
@@ -40,16 +76,58 @@ type Number struct {
 	MinimumGroupingDigits int
 }
 
+// TimeFormat contains the patterns used to format a time zone, ie: "GMT{0}"
+// and "+HH:mm;-HH:mm" for "GMT+01:00".
+type TimeFormat struct {
+	Hour           string
+	Gmt            string
+	GmtZero        string
+	Region         string
+	RegionDaylight string
+	RegionStandard string
+}
+
+// WeekData defines the weeks of a territory.
+type WeekData struct {
+	FirstDay time.Weekday
+	// the minimal number of days in the first week of the year
+	MinDays      int
+	WeekendStart time.Weekday
+	WeekendEnd   time.Weekday
+}
+
 type TimeZone string
 type NumberSystem string
 type Locale struct {
-	Name        string
+	Name string
+	// the subtags of the locale, ie: zh, Hant and HK for zh_Hant_HK
+	Language    string
+	Script      string
+	Territory   string
+	Variant     string
 	Territories map[string]*Territory
 	Currencies  map[string]*Currency
 	TimeZones   map[string]TimeZone
 	Calendars   map[string]*Calendar
-	Parent      *Locale
+	Parent      *Locale `json:"-"`
 	Number      *Number
+	Fields      map[string]*Field
+	PluralRules map[string]string
+	TimeFormat  *TimeFormat
+	// metazone => name key => name, the key is <width>_<type>: long_standard
+	MetaZoneNames map[string]map[string]string
+	// time zone => name key => name, the "city" key is the exemplar city
+	ZoneNames map[string]map[string]string
+}
+
+// LanguageMatch is the distance between a desired and a supported locale,
+// the fields are patterns: en_*_$enUS, with * matching any value and
+// $var (or $!var) the regions of a variable.
+type LanguageMatch struct {
+	Desired   string
+	Supported string
+	Distance  int
+	Oneway    bool
 }
 
 type Symbol struct {
@@ -92,6 +170,42 @@ func (locale *Locale) GetSymbol(system string) *Symbol {
 	return nil
 }
 
+// GetTerritoryName returns the name of the territory, an alt variant
+// (short, variant) falls back to the name: "UK" or "United Kingdom".
+func (locale *Locale) GetTerritoryName(code, alt string) string {
+	for l := locale; alt != "" && l != nil; l = l.Parent {
+		if territory, ok := l.Territories[code]; ok && territory.Alts[alt] != "" {
+			return territory.Alts[alt]
+		}
+	}
+
+	for l := locale; l != nil; l = l.Parent {
+		if territory, ok := l.Territories[code]; ok && territory.Name != "" {
+			return territory.Name
+		}
+	}
+
+	return ""
+}
+
+// GetCurrencySymbol returns the symbol of the currency, an alt variant
+// (narrow, variant, formal) falls back to the symbol: "$" or "US$".
+func (locale *Locale) GetCurrencySymbol(code, alt string) string {
+	for l := locale; alt != "" && l != nil; l = l.Parent {
+		if currency, ok := l.Currencies[code]; ok && currency.Alts[alt] != "" {
+			return currency.Alts[alt]
+		}
+	}
+
+	for l := locale; l != nil; l = l.Parent {
+		if currency, ok := l.Currencies[code]; ok && currency.Symbol != "" {
+			return currency.Symbol
+		}
+	}
+
+	return ""
+}
+
 func (locale *Locale) String() string {
 	return locale.Name
 }
@@ -160,16 +274,229 @@ func (locale *Locale) GetCalendarLabels(system, name string) []string {
 	return nil
 }
 
-func (locale *Locale) GetCalendarFormatter(system, name string) CalendarFormatter {
+// GetCalendarLabel returns a single label, the parents are used if the
+// locale only defines some of the labels, ie: the japanese eras.
+func (locale *Locale) GetCalendarLabel(system, name string, index int) string {
+	if calendar, ok := locale.Calendars[system]; ok {
+		if labels, ok := calendar.Labels[name]; ok {
+			if index >= 0 && index < len(labels) && labels[index] != "" {
+				return labels[index]
+			}
+		}
+	}
+
+	if locale.Parent != nil {
+		return locale.Parent.GetCalendarLabel(system, name, index)
+	}
+
+	return ""
+}
+
+// GetDayPeriodLabel returns the label of the day period of the time, the
+// time is a hhmm value: 1230. The parents are used if the locale does not
+// define a period matching the time, an empty string is returned if no
+// locale defines one.
+func (locale *Locale) GetDayPeriodLabel(system, name string, hhmm int) string {
+	if calendar, ok := locale.Calendars[system]; ok {
+		for _, p := range calendar.DayPeriods[name] {
+			if p.Contains(hhmm) {
+				return p.Label
+			}
+		}
+	}
+
+	if locale.Parent != nil {
+		return locale.Parent.GetDayPeriodLabel(system, name, hhmm)
+	}
+
+	return ""
+}
+
+func (locale *Locale) GetCalendarPattern(system, name string) string {
+	if calendar, ok := locale.Calendars[system]; ok {
+		if pattern, ok := calendar.Patterns[name]; ok {
+			return pattern
+		}
+	}
+
+	if locale.Parent != nil {
+		return locale.Parent.GetCalendarPattern(system, name)
+	}
+
+	return ""
+}
+
+func (locale *Locale) GetCalendarSkeleton(system, skeleton string) string {
 	if calendar, ok := locale.Calendars[system]; ok {
-		if formatter, ok := calendar.Formatters[name]; ok {
-			return formatter
+		if pattern, ok := calendar.Skeletons[skeleton]; ok {
+			return pattern
 		}
 	}
 
 	if locale.Parent != nil {
-		return locale.Parent.GetCalendarFormatter(system, name)
+		return locale.Parent.GetCalendarSkeleton(system, skeleton)
+	}
+
+	return ""
+}
+
+// GetCalendarSkeletons returns all the skeletons available for the calendar,
+// the ones defined by the locale override the ones of the parents.
+func (locale *Locale) GetCalendarSkeletons(system string) map[string]string {
+	skeletons := map[string]string{}
+	if locale.Parent != nil {
+		skeletons = locale.Parent.GetCalendarSkeletons(system)
+	}
+
+	if calendar, ok := locale.Calendars[system]; ok {
+		for skeleton, pattern := range calendar.Skeletons {
+			skeletons[skeleton] = pattern
+		}
+	}
+
+	return skeletons
+}
+
+func (locale *Locale) GetCalendarInterval(system, skeleton, field string) string {
+	if calendar, ok := locale.Calendars[system]; ok {
+		if intervals, ok := calendar.Intervals[skeleton]; ok {
+			if pattern, ok := intervals[field]; ok {
+				return pattern
+			}
+		}
+	}
+
+	if locale.Parent != nil {
+		return locale.Parent.GetCalendarInterval(system, skeleton, field)
+	}
+
+	return ""
+}
+
+// GetCalendarIntervals returns all the interval patterns available for the
+// calendar, indexed by skeleton and greatest different field.
+func (locale *Locale) GetCalendarIntervals(system string) map[string]map[string]string {
+	intervals := map[string]map[string]string{}
+	if locale.Parent != nil {
+		intervals = locale.Parent.GetCalendarIntervals(system)
+	}
+
+	if calendar, ok := locale.Calendars[system]; ok {
+		for skeleton, patterns := range calendar.Intervals {
+			if _, ok := intervals[skeleton]; !ok {
+				intervals[skeleton] = map[string]string{}
+			}
+
+			for field, pattern := range patterns {
+				intervals[skeleton][field] = pattern
+			}
+		}
+	}
+
+	return intervals
+}
+
+func (locale *Locale) GetFieldRelative(name, offset string) string {
+	if field, ok := locale.Fields[name]; ok {
+		if label, ok := field.Relative[offset]; ok {
+			return label
+		}
+	}
+
+	if locale.Parent != nil {
+		return locale.Parent.GetFieldRelative(name, offset)
+	}
+
+	return ""
+}
+
+func (locale *Locale) GetFieldRelativeTime(name string, future bool, count string) string {
+	if field, ok := locale.Fields[name]; ok {
+		patterns := field.Past
+		if future {
+			patterns = field.Future
+		}
+
+		if pattern, ok := patterns[count]; ok {
+			return pattern
+		}
+	}
+
+	if locale.Parent != nil {
+		return locale.Parent.GetFieldRelativeTime(name, future, count)
+	}
+
+	return ""
+}
+
+func (locale *Locale) GetPluralRules() map[string]string {
+	if len(locale.PluralRules) > 0 {
+		return locale.PluralRules
+	}
+
+	if locale.Parent != nil {
+		return locale.Parent.GetPluralRules()
 	}
 
 	return nil
 }
+
+// GetTimeFormat returns the time zone formats, the values not defined by
+// the locale are read from the parents.
+func (locale *Locale) GetTimeFormat() *TimeFormat {
+	format := &TimeFormat{}
+	if locale.Parent != nil {
+		format = locale.Parent.GetTimeFormat()
+	}
+
+	if locale.TimeFormat == nil {
+		return format
+	}
+
+	for _, v := range []struct {
+		value  string
+		target *string
+	}{
+		{locale.TimeFormat.Hour, &format.Hour},
+		{locale.TimeFormat.Gmt, &format.Gmt},
+		{locale.TimeFormat.GmtZero, &format.GmtZero},
+		{locale.TimeFormat.Region, &format.Region},
+		{locale.TimeFormat.RegionDaylight, &format.RegionDaylight},
+		{locale.TimeFormat.RegionStandard, &format.RegionStandard},
+	} {
+		if v.value != "" {
+			*v.target = v.value
+		}
+	}
+
+	return format
+}
+
+func (locale *Locale) GetMetaZoneName(metazone, key string) string {
+	if names, ok := locale.MetaZoneNames[metazone]; ok {
+		if name, ok := names[key]; ok {
+			return name
+		}
+	}
+
+	if locale.Parent != nil {
+		return locale.Parent.GetMetaZoneName(metazone, key)
+	}
+
+	return ""
+}
+
+// GetZoneName returns the name of the zone, the zone is a CLDR id.
+func (locale *Locale) GetZoneName(zone, key string) string {
+	if names, ok := locale.ZoneNames[zone]; ok {
+		if name, ok := names[key]; ok {
+			return name
+		}
+	}
+
+	if locale.Parent != nil {
+		return locale.Parent.GetZoneName(zone, key)
+	}
+
+	return ""
+}
@@ -0,0 +1,124 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package dto
+
+// Supplemental contains the CLDR data shared by all the locales, it is
+// registered by the root locale package, see RegisterSupplemental.
+type Supplemental struct {
+	// time zone => metazone, ie: Europe/Paris => Europe_Central
+	MetaZones map[string]string
+	// time zone => CLDR time zone, the CLDR ids are stable and differ from
+	// the renamed IANA ids: Asia/Kolkata => Asia/Calcutta
+	ZoneAliases map[string]string
+	// CLDR time zone => short BCP 47 id, ie: Europe/Paris => frpar
+	ZoneIds map[string]string
+	// territory => week data, 001 is the default value
+	WeekData map[string]*WeekData
+	// tag => likely tag, ie: fr => fr_Latn_FR
+	LikelySubtags map[string]string
+	// territory => calendars by preference, ie: TH => buddhist, gregorian
+	CalendarPreferences map[string][]string
+	// the language matching rules, ordered from the most specific to the
+	// default ones
+	LanguageMatches []*LanguageMatch
+	// variable => regions, ie: americas => AR BO BR ...
+	MatchVariables map[string][]string
+	// the locales preferred over the other ones of the same language
+	ParadigmLocales []string
+}
+
+// GetCanonicalZone returns the CLDR id of the time zone, the names and the
+// metazones are indexed by the CLDR ids: Europe/Kyiv is Europe/Kiev.
+func (s *Supplemental) GetCanonicalZone(zone string) string {
+	if s == nil {
+		return zone
+	}
+
+	if canonical, ok := s.ZoneAliases[zone]; ok {
+		return canonical
+	}
+
+	return zone
+}
+
+// GetZoneShortId returns the short BCP 47 id of the zone, ie: frpar for
+// Europe/Paris, or an empty string.
+func (s *Supplemental) GetZoneShortId(zone string) string {
+	if s == nil {
+		return ""
+	}
+
+	return s.ZoneIds[s.GetCanonicalZone(zone)]
+}
+
+// GetMetaZone returns the metazone of the zone, the zone is a CLDR id.
+func (s *Supplemental) GetMetaZone(zone string) string {
+	if s == nil {
+		return ""
+	}
+
+	return s.MetaZones[zone]
+}
+
+// GetWeekData returns the week data of the territory, or the default one
+// (001) if the territory does not define it.
+func (s *Supplemental) GetWeekData(territory string) *WeekData {
+	if s == nil {
+		return nil
+	}
+
+	if data, ok := s.WeekData[territory]; ok {
+		return data
+	}
+
+	return s.WeekData["001"]
+}
+
+func (s *Supplemental) GetLikelySubtags(tag string) string {
+	if s == nil {
+		return ""
+	}
+
+	return s.LikelySubtags[tag]
+}
+
+// GetCalendarPreferences returns the calendars used in the territory by
+// order of preference, or the default ones (001).
+func (s *Supplemental) GetCalendarPreferences(territory string) []string {
+	if s == nil {
+		return nil
+	}
+
+	if calendars, ok := s.CalendarPreferences[territory]; ok {
+		return calendars
+	}
+
+	return s.CalendarPreferences["001"]
+}
+
+func (s *Supplemental) GetLanguageMatches() []*LanguageMatch {
+	if s == nil {
+		return nil
+	}
+
+	return s.LanguageMatches
+}
+
+func (s *Supplemental) GetMatchVariable(name string) []string {
+	if s == nil {
+		return nil
+	}
+
+	return s.MatchVariables[name]
+}
+
+func (s *Supplemental) GetParadigmLocales() []string {
+	if s == nil {
+		return nil
+	}
+
+	return s.ParadigmLocales
+}
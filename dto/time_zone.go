@@ -0,0 +1,170 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// getTimeZoneId returns the CLDR id of the time location.
+func getTimeZoneId(t time.Time) string {
+	zone := t.Location().String()
+	if zone == "UTC" {
+		return "Etc/UTC"
+	}
+
+	return zone
+}
+
+// GetTimeZoneName returns the name of the zone, or the name of its
+// metazone, the key is <width>_<type>: long_standard, short_generic.
+func (locale *Locale) GetTimeZoneName(zone, key string) string {
+	zone = GetSupplemental().GetCanonicalZone(zone)
+
+	if name := locale.GetZoneName(zone, key); name != "" {
+		return name
+	}
+
+	if metazone := GetSupplemental().GetMetaZone(zone); metazone != "" {
+		return locale.GetMetaZoneName(metazone, key)
+	}
+
+	return ""
+}
+
+// GetExemplarCity returns the localized city of the zone, or the city from
+// the zone id: America/Los_Angeles is "Los Angeles".
+func (locale *Locale) GetExemplarCity(zone string) string {
+	if city := locale.GetZoneName(GetSupplemental().GetCanonicalZone(zone), "city"); city != "" {
+		return city
+	}
+
+	if i := strings.LastIndex(zone, "/"); i >= 0 {
+		zone = zone[i+1:]
+	}
+
+	return strings.ReplaceAll(zone, "_", " ")
+}
+
+// FormatZoneShortId returns the short BCP 47 id of the time zone: "frpar"
+// for Europe/Paris, or an empty string if the id is not known.
+func (locale *Locale) FormatZoneShortId(t time.Time) string {
+	return GetSupplemental().GetZoneShortId(getTimeZoneId(t))
+}
+
+// FormatSpecificZoneName returns the standard or the daylight name of the
+// time zone, depending on the instant: "Central European Summer Time".
+// The localized GMT format is used if the locale does not define a name.
+func (locale *Locale) FormatSpecificZoneName(t time.Time, long bool) string {
+	key := "short_standard"
+	if long {
+		key = "long_standard"
+	}
+
+	if t.IsDST() {
+		key = strings.Replace(key, "standard", "daylight", 1)
+	}
+
+	if name := locale.GetTimeZoneName(getTimeZoneId(t), key); name != "" {
+		return name
+	}
+
+	return locale.FormatGMT(t, long)
+}
+
+// FormatGenericZoneName returns the generic name of the time zone, which
+// does not depend on the instant: "Central European Time". It falls back
+// to the location format, then to the localized GMT format.
+func (locale *Locale) FormatGenericZoneName(t time.Time, long bool) string {
+	key := "short_generic"
+	if long {
+		key = "long_generic"
+	}
+
+	if name := locale.GetTimeZoneName(getTimeZoneId(t), key); name != "" {
+		return name
+	}
+
+	if name := locale.FormatZoneLocation(t); name != "" {
+		return name
+	}
+
+	return locale.FormatGMT(t, long)
+}
+
+// FormatZoneLocation returns the generic location format: "Paris Time",
+// the value is empty if the zone does not have a location.
+func (locale *Locale) FormatZoneLocation(t time.Time) string {
+	zone := getTimeZoneId(t)
+
+	region := locale.GetTimeFormat().Region
+	if region == "" || !strings.Contains(zone, "/") || strings.HasPrefix(zone, "Etc/") {
+		return ""
+	}
+
+	return strings.Replace(region, "{0}", locale.GetExemplarCity(zone), 1)
+}
+
+// FormatGMT returns the localized GMT format of the offset, the long form
+// is "GMT-08:00", the short form removes the leading zero and the zero
+// minutes: "GMT-8".
+func (locale *Locale) FormatGMT(t time.Time, long bool) string {
+	_, offset := t.Zone()
+
+	format := locale.GetTimeFormat()
+
+	if offset == 0 {
+		if format.GmtZero != "" {
+			return format.GmtZero
+		}
+
+		return "GMT"
+	}
+
+	gmt := format.Gmt
+	if gmt == "" {
+		gmt = "GMT{0}"
+	}
+
+	hour := format.Hour
+	if hour == "" {
+		hour = "+HH:mm;-HH:mm"
+	}
+
+	positive, negative, found := strings.Cut(hour, ";")
+	if !found {
+		negative = "-" + positive
+	}
+
+	pattern := positive
+	if offset < 0 {
+		pattern = negative
+		offset = -offset
+	}
+
+	hours, minutes := offset/3600, offset/60%60
+
+	if !long {
+		pattern = strings.Replace(pattern, "HH", "H", 1)
+
+		if minutes == 0 {
+			// remove the minutes and their separator
+			if i := strings.Index(pattern, "mm"); i > 0 {
+				pattern = pattern[:i-1] + pattern[i+2:]
+			}
+		}
+	}
+
+	value := strings.NewReplacer(
+		"HH", fmt.Sprintf("%02d", hours),
+		"H", fmt.Sprintf("%d", hours),
+		"mm", fmt.Sprintf("%02d", minutes),
+	).Replace(pattern)
+
+	return strings.Replace(gmt, "{0}", value, 1)
+}
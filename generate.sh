@@ -3,23 +3,35 @@
 
 mkdir -p data
 
-if [ ! -d "data/common" ]; then
+# the generator reads the archive, no need to unzip it
+if [ ! -f "data/core.zip" ]; then
   echo "Download the CLDR data"
   
   curl https://unicode.org/Public/cldr/44/core.zip -o data/core.zip
-  (cd data && unzip core.zip)
 else
   echo "CLDR data already exists"
 fi
 
-export PATH=$PATH:`go env GOPATH`/bin
-export CLDR_DIR=`pwd`/data/common
-export LOCALE_DIR=`pwd`/locales
-
 mkdir -p locales
 rm -rf locales/*
 
-(cd generator && go run .)
+# the arguments are passed to the generator: ./generate.sh -glob "fr*" -coverage modern
+(cd generator && go run ./cmd/generator -cldr ../data/core.zip -out ../locales "$@")
+
+# the locales are loaded and written by -workers goroutines, with -continue
+# the locales not inheriting from a failed locale are still generated and
+# the failures are reported at the end.
+
+# the generated locales are validated, the generator exits with 1 if a value
+# used by the formatters is missing, the issues are written with -report:
+# (cd generator && go run ./cmd/generator -cldr ../data/core.zip -out ../locales -report ../locales/report.json)
+
+# the cldr-json npm packages (cldr-core, cldr-numbers-full, cldr-dates-full,
+# cldr-localenames-full, cldr-annotations-full) can be used instead of core.zip:
+# (cd generator && go run ./cmd/generator -json ../node_modules -out ../locales)
+
+# the changes of a CLDR release are reported with the diff mode:
+# (cd generator && go run ./cmd/generator -cldr ../data/core.zip -diff ../data/43/core.zip -all)
 
 # cat locales/fr/fr.go
 
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/rande/golocales/generator"
+)
+
+// splitList splits a comma separated flag value.
+func splitList(value string) []string {
+	list := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run generates the locales and returns the exit code, the deferred calls
+// are done before exiting.
+func run() int {
+	var DiffPath, ReportPath, locales, globs, alts string
+
+	options := &generator.Options{Selection: &generator.Selection{}}
+
+	flag.StringVar(&options.CldrPath, "cldr", "", "the CLDR common folder or the core.zip archive, ie: data/core.zip")
+	flag.StringVar(&options.JsonPath, "json", "", "the folder of the cldr-json packages (cldr-core, cldr-numbers-full, ...), used instead of -cldr")
+	flag.StringVar(&options.LocalePath, "out", "", "the folder of the generated locales")
+	flag.StringVar(&DiffPath, "diff", "", "the CLDR common folder or the core.zip archive of a previous release, the changes of the locales are reported instead of generated")
+	flag.StringVar(&ReportPath, "report", "", "the JSON file of the validation report of the generated locales")
+	flag.StringVar(&locales, "locales", "", "comma separated locales to generate, ie: fr,de_CH")
+	flag.StringVar(&globs, "glob", "", "comma separated globs matching the locales to generate, ie: fr_*,de*")
+	flag.BoolVar(&options.Selection.All, "all", false, "generate all the locales")
+	flag.StringVar(&options.Selection.Coverage, "coverage", "", "the minimum coverage level of the locales: "+strings.Join(generator.CoverageLevels, ", "))
+	flag.StringVar(&options.DraftLevel, "draft", generator.DefaultDraftLevel, "the minimal draft status of the values: "+strings.Join(generator.DraftLevels, ", "))
+	flag.StringVar(&options.Format, "format", generator.FormatGo, "the output format of the locales, root excepted: "+strings.Join(generator.OutputFormats, ", "))
+	flag.IntVar(&options.Workers, "workers", runtime.NumCPU(), "the number of locales loaded and written at the same time")
+	flag.BoolVar(&options.ContinueOnError, "continue", false, "continue if a locale fails, the failed locales are reported at the end")
+	flag.StringVar(&alts, "alt", "", "comma separated alt variants of the display names to keep, ie: short,variant, all by default")
+	flag.Parse()
+
+	if options.CldrPath == "" && options.JsonPath == "" {
+		fmt.Printf("-cldr or -json is not set\n")
+		return -1
+	}
+
+	if options.LocalePath == "" && DiffPath == "" {
+		fmt.Printf("-out is not set\n")
+		return -1
+	}
+
+	options.Alts = splitList(alts)
+	options.Selection.Locales = splitList(locales)
+	options.Selection.Globs = splitList(globs)
+
+	if !options.Selection.All && len(options.Selection.Locales) == 0 && len(options.Selection.Globs) == 0 {
+		options.Selection.Locales = generator.DefaultLocales
+	}
+
+	g, err := generator.New(options)
+	if err != nil {
+		fmt.Printf("%s\n", err.Error())
+		return -1
+	}
+
+	if err := g.Load(); err != nil {
+		fmt.Printf("%s\n", err.Error())
+		return -1
+	}
+
+	defer g.Close()
+
+	if DiffPath != "" {
+		return diff(g, DiffPath)
+	}
+
+	errs, err := g.Generate()
+	if err != nil {
+		fmt.Printf("%s\n", err.Error())
+		return -1
+	}
+
+	if len(errs) > 0 && !options.ContinueOnError {
+		fmt.Printf("\n")
+		generator.WriteLocaleErrors(os.Stdout, errs)
+		return 1
+	}
+
+	report, err := generator.ValidateCLDR(g.CLDR)
+	if err != nil {
+		fmt.Printf("%s\n", err.Error())
+		return -1
+	}
+
+	if ReportPath != "" {
+		f, err := os.Create(ReportPath)
+		if err != nil {
+			fmt.Printf("%s\n", err.Error())
+			return -1
+		}
+
+		err = generator.WriteReport(f, report)
+		f.Close()
+
+		if err != nil {
+			fmt.Printf("%s\n", err.Error())
+			return -1
+		}
+	}
+
+	fmt.Printf("\n")
+	generator.WriteReportSummary(os.Stdout, report)
+
+	if len(errs) > 0 {
+		fmt.Printf("\n")
+		generator.WriteLocaleErrors(os.Stdout, errs)
+	}
+
+	// the locales are generated but the runtime would panic or return empty
+	// values
+	if report.Errors > 0 || len(errs) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// diff writes the changes of the selected locales between the previous
+// release and the loaded one, the exit code is returned.
+func diff(g *generator.Generator, path string) int {
+	cldr := g.CLDR
+
+	codes, err := generator.SelectLocales(cldr, g.Options.Selection)
+	if err != nil {
+		fmt.Printf("%s\n", err.Error())
+		return -1
+	}
+
+	old, err := generator.LoadCLDR(path)
+	if err != nil {
+		fmt.Printf("%s\n", err.Error())
+		return -1
+	}
+
+	defer old.Close()
+
+	old.DraftLevel = cldr.DraftLevel
+	old.Alts = cldr.Alts
+
+	// the locales removed in the release are reported too
+	if previous, err := generator.SelectLocales(old, g.Options.Selection); err == nil {
+		codes = append(codes, previous...)
+		slices.Sort(codes)
+		codes = slices.Compact(codes)
+	}
+
+	changes, err := generator.DiffCLDR(old, cldr, codes)
+	if err != nil {
+		fmt.Printf("%s\n", err.Error())
+		return -1
+	}
+
+	generator.WriteDiff(os.Stdout, old, cldr, changes)
+
+	return 0
+}
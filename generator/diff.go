@@ -0,0 +1,193 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// Change is a value changed between two CLDR releases, an empty value is a
+// value added or removed.
+type Change struct {
+	Locale string
+	// symbol, pattern, currency_digits or territory
+	Kind string
+	// the path of the value, ie: gregorian.date_short or latn.Decimal
+	Key string
+	Old string
+	New string
+}
+
+// DiffCLDR compares the locales of two CLDR releases, only the values
+// defined by the locales are compared, not the inherited ones. The currency
+// digits are reported on the root locale.
+func DiffCLDR(old, new *CLDR, codes []string) ([]*Change, error) {
+	noop := func(locale *Locale) {}
+
+	oldRoot, err := old.GetLocale("root", noop)
+	if err != nil {
+		return nil, err
+	}
+
+	newRoot, err := new.GetLocale("root", noop)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []*Change{}
+	changes = append(changes, diffValues("root", "currency_digits", getCurrencyDigits(old), getCurrencyDigits(new))...)
+	changes = append(changes, DiffLocales(oldRoot, newRoot)...)
+
+	for _, code := range codes {
+		o, err := getReleaseLocale(old, code)
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := getReleaseLocale(new, code)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, DiffLocales(o, n)...)
+	}
+
+	return changes, nil
+}
+
+// getReleaseLocale returns the locale, nil if the locale does not exist in
+// the release.
+func getReleaseLocale(cldr *CLDR, code string) (*Locale, error) {
+	if found, err := cldr.HasLocale(code); err != nil || !found {
+		return nil, err
+	}
+
+	return cldr.GetLocale(code, func(locale *Locale) {})
+}
+
+// DiffLocales compares the symbols, the patterns and the territory names
+// of a locale, a nil locale does not exist in the release.
+func DiffLocales(old, new *Locale) []*Change {
+	code := ""
+	values := [2]map[string]map[string]string{}
+
+	for i, locale := range []*Locale{old, new} {
+		values[i] = map[string]map[string]string{"symbol": {}, "pattern": {}, "territory": {}}
+		if locale == nil {
+			continue
+		}
+
+		code = locale.Code
+
+		for system, symbol := range locale.Number.Symbols {
+			v := reflect.ValueOf(*symbol)
+			for f := 0; f < v.NumField(); f++ {
+				if name := v.Type().Field(f).Name; name != "System" && v.Field(f).Kind() == reflect.String {
+					values[i]["symbol"][system+"."+name] = v.Field(f).String()
+				}
+			}
+		}
+
+		for kind, groups := range map[string]map[string]FormatGroup{
+			"decimal":  locale.Number.Decimals,
+			"percent":  locale.Number.Percents,
+			"currency": locale.Number.Currencies,
+		} {
+			for system, group := range groups {
+				for name, formats := range group {
+					for _, f := range formats {
+						key := fmt.Sprintf("%s.%s.%s", kind, system, name)
+						for _, attr := range []string{f.Type, f.Count, f.Alt} {
+							if attr != "" {
+								key += "." + attr
+							}
+						}
+
+						values[i]["pattern"][key] = f.Pattern
+					}
+				}
+			}
+		}
+
+		for system, calendar := range locale.Calendars {
+			for name, pattern := range calendar.Patterns {
+				values[i]["pattern"][system+"."+name] = pattern
+			}
+		}
+
+		for _, t := range locale.Territories {
+			if t.Name != "" {
+				values[i]["territory"][t.Code] = t.Name
+			}
+		}
+	}
+
+	changes := []*Change{}
+	for _, kind := range []string{"symbol", "pattern", "territory"} {
+		changes = append(changes, diffValues(code, kind, values[0][kind], values[1][kind])...)
+	}
+
+	return changes
+}
+
+func diffValues(code, kind string, old, new map[string]string) []*Change {
+	keys := []string{}
+	for key := range old {
+		keys = append(keys, key)
+	}
+
+	for key := range new {
+		if _, ok := old[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	changes := []*Change{}
+	for _, key := range keys {
+		if old[key] != new[key] {
+			changes = append(changes, &Change{Locale: code, Kind: kind, Key: key, Old: old[key], New: new[key]})
+		}
+	}
+
+	return changes
+}
+
+func getCurrencyDigits(cldr *CLDR) map[string]string {
+	digits := map[string]string{}
+	for code, c := range cldr.Currencies {
+		digits[code] = c.Digits
+		digits[code+".cash"] = c.CashDigits
+	}
+
+	return digits
+}
+
+// WriteDiff writes the changes grouped by locale.
+func WriteDiff(w io.Writer, old, new *CLDR, changes []*Change) {
+	fmt.Fprintf(w, "CLDR %s => %s, %d changes\n", ifEmptyString(old.Version, "?"), ifEmptyString(new.Version, "?"), len(changes))
+
+	locale := ""
+	for _, c := range changes {
+		if c.Locale != locale {
+			locale = c.Locale
+			fmt.Fprintf(w, "\n[%s]\n", locale)
+		}
+
+		switch {
+		case c.Old == "":
+			fmt.Fprintf(w, "  + %s %s: %q\n", c.Kind, c.Key, c.New)
+		case c.New == "":
+			fmt.Fprintf(w, "  - %s %s: %q\n", c.Kind, c.Key, c.Old)
+		default:
+			fmt.Fprintf(w, "  ~ %s %s: %q => %q\n", c.Kind, c.Key, c.Old, c.New)
+		}
+	}
+}
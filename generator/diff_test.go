@@ -0,0 +1,74 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getDiffLocale(decimal, pattern, name string) *Locale {
+	return &Locale{
+		Code: "fr",
+		Number: &Number{
+			Symbols: map[string]*Symbol{"latn": {System: "latn", Decimal: decimal, Group: " "}},
+			Decimals: map[string]FormatGroup{
+				"latn": {"standard": {{Pattern: "#,##0.###"}}},
+			},
+		},
+		Calendars: map[string]*Calendar{
+			"gregorian": {System: "gregorian", Patterns: map[string]string{"date_short": pattern}},
+		},
+		Territories: map[string]*Territory{
+			"AD": {Code: "AD", Name: name},
+		},
+	}
+}
+
+func Test_Diff_Locales(t *testing.T) {
+	old := getDiffLocale(".", "dd/MM/y", "Andorre")
+	new := getDiffLocale(",", "dd/MM/y", "Principauté d’Andorre")
+	new.Territories["FR"] = &Territory{Code: "FR", Name: "France"}
+
+	changes := DiffLocales(old, new)
+	assert.Equal(t, []*Change{
+		{Locale: "fr", Kind: "symbol", Key: "latn.Decimal", Old: ".", New: ","},
+		{Locale: "fr", Kind: "territory", Key: "AD", Old: "Andorre", New: "Principauté d’Andorre"},
+		{Locale: "fr", Kind: "territory", Key: "FR", Old: "", New: "France"},
+	}, changes)
+
+	// a removed locale reports all its values
+	changes = DiffLocales(old, nil)
+	assert.Len(t, changes, 5)
+	assert.Equal(t, "decimal.latn.standard", changes[2].Key)
+	assert.Equal(t, "#,##0.###", changes[2].Old)
+
+	buffer := bytes.NewBuffer([]byte{})
+	WriteDiff(buffer, &CLDR{Version: "43"}, &CLDR{Version: "44"}, DiffLocales(old, new))
+	assert.Equal(t, "CLDR 43 => 44, 3 changes\n\n[fr]\n  ~ symbol latn.Decimal: \".\" => \",\"\n  ~ territory AD: \"Andorre\" => \"Principauté d’Andorre\"\n  + territory FR: \"France\"\n", buffer.String())
+}
+
+func Test_Attach_Version(t *testing.T) {
+	path := t.TempDir()
+
+	cldr := &CLDR{Path: path, FS: os.DirFS(path)}
+	supplemental := &SupplementalData{}
+
+	supplemental.Version.CldrVersion = "42"
+	assert.NoError(t, AttachVersion(cldr, supplemental))
+	assert.Equal(t, "42", cldr.Version)
+
+	// the attribute is declared in the DTD
+	assert.Nil(t, os.MkdirAll(path+"/dtd", 0755))
+	assert.Nil(t, os.WriteFile(path+"/dtd/ldmlSupplemental.dtd", []byte("<!ATTLIST version cldrVersion CDATA #FIXED \"44\" >\n"), 0644))
+
+	supplemental.Version.CldrVersion = ""
+	assert.NoError(t, AttachVersion(cldr, supplemental))
+	assert.Equal(t, "44", cldr.Version)
+}
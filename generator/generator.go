@@ -0,0 +1,317 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package generator loads the CLDR data and generates the locales used by
+// golocales. The generator command in cmd/generator is a thin wrapper, the
+// package can be used to generate extra data with plugins:
+//
+//	g, err := generator.New(&generator.Options{
+//		CldrPath:   "data/core.zip",
+//		LocalePath: "locales",
+//		Selection:  &generator.Selection{Locales: []string{"fr"}},
+//		Plugins:    []generator.Plugin{&MyPlugin{}},
+//	})
+package generator
+
+import (
+	"embed"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+//go:embed all:templates
+var content embed.FS
+
+func GetEmbedFS() embed.FS {
+	return content
+}
+
+// DefaultLocales are the locales generated when no locale is selected.
+var DefaultLocales = []string{
+	"en", "fr", "fr_CA", "sr", "es", "hi", "en_US", "de_CH",
+	"de", "fr_FR", "de_AT", "en_NL",
+	"sr_Latn", "zh", "zh_Hant", "zh_Hant_HK", "uz", "uz_Cyrl",
+}
+
+// SupplementalHandler attaches the data of a supplemental file to the CLDR,
+// like AttachPluralRules. The elements not decoded by SupplementalData are
+// available in supplemental.Nodes. An error stops the generation.
+type SupplementalHandler func(cldr *CLDR, supplemental *SupplementalData) error
+
+// LdmlHandler attaches the data of the locale document to the locale, like
+// AttachNumber. The elements not decoded by Ldml are available in
+// locale.Document. The handlers of the locales are called by the workers,
+// a handler must only update its locale. An error stops the loading of the
+// locale.
+type LdmlHandler func(locale *Locale, cldr *CLDR, ldml *Ldml) error
+
+// Plugin extends the generator, the plugin registers its handlers and its
+// templates when the generator is created.
+type Plugin interface {
+	Register(g *Generator) error
+}
+
+// Template is a template of a plugin, the generated file is named after the
+// template: locales/fr/<Name>.go for a locale template.
+type Template struct {
+	Name    string
+	Content string
+}
+
+// Options configures the generator, the empty values are the default ones.
+type Options struct {
+	// the CLDR common folder or the core.zip archive
+	CldrPath string
+	// the folder of the cldr-json packages, used instead of CldrPath
+	JsonPath string
+	// the folder of the generated locales, the folder must exist
+	LocalePath string
+	// the locales to generate, DefaultLocales if nil
+	Selection *Selection
+	// the minimal draft status of the values, DefaultDraftLevel if empty
+	DraftLevel string
+	// the alt variants of the display names to keep, all if empty
+	Alts []string
+	// the output format of the locales, FormatGo if empty
+	Format string
+	// the number of locales loaded at the same time, see Pipeline
+	Workers         int
+	ContinueOnError bool
+	Plugins         []Plugin
+}
+
+type supplementalFile struct {
+	Name    string
+	Handler SupplementalHandler
+}
+
+// Generator loads the CLDR and writes the locales with the handlers and
+// the templates of the plugins.
+type Generator struct {
+	Options *Options
+	// the loaded CLDR, see Load
+	CLDR *CLDR
+
+	supplementalFiles []*supplementalFile
+	ldmlHandlers      []LdmlHandler
+	localeTemplates   []*Template
+	templates         []*Template
+}
+
+// New checks the options and registers the plugins, the options are copied
+// with their default values.
+func New(opts *Options) (*Generator, error) {
+	options := *opts
+
+	if options.Selection == nil {
+		options.Selection = &Selection{Locales: DefaultLocales}
+	}
+
+	options.DraftLevel = ifEmptyString(options.DraftLevel, DefaultDraftLevel)
+	options.Format = ifEmptyString(options.Format, FormatGo)
+
+	if !slices.Contains(DraftLevels, options.DraftLevel) {
+		return nil, fmt.Errorf("unknown draft status %q, expected one of %s", options.DraftLevel, strings.Join(DraftLevels, ", "))
+	}
+
+	if !slices.Contains(OutputFormats, options.Format) {
+		return nil, fmt.Errorf("unknown format %q, expected one of %s", options.Format, strings.Join(OutputFormats, ", "))
+	}
+
+	g := &Generator{Options: &options}
+
+	for _, plugin := range options.Plugins {
+		if err := plugin.Register(g); err != nil {
+			return nil, err
+		}
+	}
+
+	// the templates are checked before loading anything
+	for _, t := range append(slices.Clone(g.localeTemplates), g.templates...) {
+		if _, err := template.New(t.Name).Parse(t.Content); err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", t.Name, err)
+		}
+	}
+
+	return g, nil
+}
+
+// AddSupplementalHandler registers a handler of a file of the CLDR common
+// folder, ie: supplemental/myData.xml with a supplementalData root element.
+// The file is loaded after the built-in files, a built-in file can be used
+// too.
+func (g *Generator) AddSupplementalHandler(name string, handler SupplementalHandler) {
+	g.supplementalFiles = append(g.supplementalFiles, &supplementalFile{Name: name, Handler: handler})
+}
+
+// AddLdmlHandler registers a handler called after the built-in ones for
+// each locale.
+func (g *Generator) AddLdmlHandler(handler LdmlHandler) {
+	g.ldmlHandlers = append(g.ldmlHandlers, handler)
+}
+
+// AddLocaleTemplate registers a template generated in the package of each
+// locale, alongside locale.tmpl, with the same context: .Locale, .Code,
+// ... The locale templates are not generated for the data files.
+func (g *Generator) AddLocaleTemplate(name, content string) {
+	g.localeTemplates = append(g.localeTemplates, &Template{Name: name, Content: content})
+}
+
+// AddTemplate registers a template generated once in the locales folder,
+// with the same context as locales.tmpl: .Cldr
+func (g *Generator) AddTemplate(name, content string) {
+	g.templates = append(g.templates, &Template{Name: name, Content: content})
+}
+
+// Load loads the CLDR with the supplemental files of the plugins.
+func (g *Generator) Load() error {
+	load := LoadCLDR
+	path := g.Options.CldrPath
+
+	if g.Options.JsonPath != "" {
+		load, path = LoadCLDRJson, g.Options.JsonPath
+	}
+
+	cldr, err := load(path)
+	if err != nil {
+		return err
+	}
+
+	cldr.DraftLevel = g.Options.DraftLevel
+	cldr.Alts = g.Options.Alts
+	cldr.LdmlHandlers = g.ldmlHandlers
+
+	for _, f := range g.supplementalFiles {
+		fmt.Printf(" > Loading plugin file: %s\n", f.Name)
+
+		if err := cldr.LoadSupplemental(f.Name, f.Handler); err != nil {
+			cldr.Close()
+			return fmt.Errorf("unable to load the plugin file %s: %w", f.Name, err)
+		}
+	}
+
+	g.CLDR = cldr
+
+	return nil
+}
+
+// Close releases the CLDR files.
+func (g *Generator) Close() error {
+	if g.CLDR == nil {
+		return nil
+	}
+
+	return g.CLDR.Close()
+}
+
+// Generate writes the root locale, the selected locales and their parents,
+// the failed locales are returned, see Pipeline. The CLDR is loaded if
+// needed.
+func (g *Generator) Generate() ([]*LocaleError, error) {
+	if g.CLDR == nil {
+		if err := g.Load(); err != nil {
+			return nil, err
+		}
+	}
+
+	cldr, path := g.CLDR, g.Options.LocalePath
+
+	codes, err := SelectLocales(cldr, g.Options.Selection)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("\nLoading root locale\n")
+	if _, err := cldr.GetLocale("root", func(locale *Locale) {}); err != nil {
+		return nil, err
+	}
+
+	for _, name := range []string{"territories", "timezones", "currencies"} {
+		if err := WriteGo(name, path, cldr); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := g.writeLocale(cldr.RootLocale, FormatGo); err != nil {
+		return nil, err
+	}
+
+	// the base language is generated too, even if the locale does not
+	// inherit from it: sr_Latn
+	for _, code := range codes {
+		language, _, found := strings.Cut(code, "_")
+		if !found {
+			continue
+		}
+
+		exists, err := cldr.HasLocale(language)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			codes = append(codes, language)
+		}
+	}
+
+	pipeline := &Pipeline{
+		Workers:         g.Options.Workers,
+		ContinueOnError: g.Options.ContinueOnError,
+		Loaded: func(locale *Locale) error {
+			return g.writeLocale(locale, g.Options.Format)
+		},
+	}
+
+	fmt.Printf("\nLoading locales\n")
+	errs := pipeline.Run(cldr, codes)
+	if len(errs) > 0 && !g.Options.ContinueOnError {
+		return errs, nil
+	}
+
+	if err := WriteGo("locales", path, cldr); err != nil {
+		return errs, err
+	}
+
+	if g.Options.Format == FormatData {
+		err = WriteData(path, cldr)
+	} else {
+		err = WriteAll(path, cldr)
+	}
+
+	if err != nil {
+		return errs, err
+	}
+
+	for _, t := range g.templates {
+		if err := WriteTemplate(path, cldr, t); err != nil {
+			return errs, err
+		}
+	}
+
+	return errs, nil
+}
+
+// writeLocale writes the locale and the locale templates of the plugins.
+func (g *Generator) writeLocale(locale *Locale, format string) error {
+	if format == FormatData {
+		fmt.Printf("> Generate data file %s\n", locale.Code)
+		return WriteLocaleData(g.Options.LocalePath, locale)
+	}
+
+	fmt.Printf("> Generate file module %s\n", locale.Code)
+	if err := WriteLocale(g.Options.LocalePath, locale); err != nil {
+		return err
+	}
+
+	for _, t := range g.localeTemplates {
+		if err := WriteLocaleTemplate(g.Options.LocalePath, locale, t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
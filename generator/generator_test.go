@@ -0,0 +1,133 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// teamPlugin attaches the team of each locale, defined in a custom
+// supplemental file.
+type teamPlugin struct{}
+
+func (p *teamPlugin) Register(g *Generator) error {
+	g.AddSupplementalHandler("supplemental/teams.xml", func(cldr *CLDR, supplemental *SupplementalData) error {
+		teams := map[string]string{}
+		for _, n := range supplemental.Nodes {
+			for _, team := range n.Nodes {
+				teams[team.GetAttr("locale")] = team.Text
+			}
+		}
+
+		cldr.Extra["teams"] = teams
+
+		return nil
+	})
+
+	g.AddLdmlHandler(func(locale *Locale, cldr *CLDR, ldml *Ldml) error {
+		teams, ok := cldr.Extra["teams"].(map[string]string)
+		if !ok {
+			return errors.New("the teams are not loaded")
+		}
+
+		locale.Extra["team"] = teams[locale.Code]
+
+		return nil
+	})
+
+	g.AddLocaleTemplate("team", "package {{ .Code }}\n\nconst  Team = {{ printf \"%q\" (index .Locale.Extra \"team\") }}\n")
+	g.AddTemplate("teams", "package locales\n\nvar Teams = map[string]string{\n{{- range $code, $team := index .Cldr.Extra \"teams\" }}\n{{ printf \"%q\" $code }}: {{ printf \"%q\" $team }},\n{{- end }}\n}\n")
+
+	return nil
+}
+
+type failingPlugin struct{}
+
+func (p *failingPlugin) Register(g *Generator) error {
+	return errors.New("unable to register")
+}
+
+func Test_Generator_New(t *testing.T) {
+	g, err := New(&Options{CldrPath: "fixtures/core.zip"})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultLocales, g.Options.Selection.Locales)
+	assert.Equal(t, DefaultDraftLevel, g.Options.DraftLevel)
+	assert.Equal(t, FormatGo, g.Options.Format)
+
+	_, err = New(&Options{Format: "yaml"})
+	assert.EqualError(t, err, "unknown format \"yaml\", expected one of go, data")
+
+	_, err = New(&Options{Plugins: []Plugin{&failingPlugin{}}})
+	assert.EqualError(t, err, "unable to register")
+
+	_, err = New(&Options{Plugins: []Plugin{pluginFunc(func(g *Generator) error {
+		g.AddLocaleTemplate("broken", "{{ .Code ")
+		return nil
+	})}})
+	assert.ErrorContains(t, err, "invalid template broken")
+}
+
+type pluginFunc func(g *Generator) error
+
+func (f pluginFunc) Register(g *Generator) error {
+	return f(g)
+}
+
+func Test_Generator_Plugin(t *testing.T) {
+	path := t.TempDir()
+	out := t.TempDir()
+
+	// the converted files are written in a folder with the plugin file
+	common, err := ConvertJson("fixtures/cldr-json")
+	assert.NoError(t, err)
+
+	for name, file := range common.(fstest.MapFS) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path+"/"+name), 0755))
+		assert.NoError(t, os.WriteFile(path+"/"+name, file.Data, 0644))
+	}
+
+	assert.NoError(t, os.WriteFile(path+"/supplemental/teams.xml", []byte(`<supplementalData><teams><team locale="fr">Paris</team><team locale="fr_CA">Montréal</team></teams></supplementalData>`), 0644))
+
+	g, err := New(&Options{
+		CldrPath:   path,
+		LocalePath: out,
+		Selection:  &Selection{Locales: []string{"fr_CA"}},
+		Workers:    2,
+		Plugins:    []Plugin{&teamPlugin{}},
+	})
+	assert.NoError(t, err)
+
+	defer g.Close()
+
+	errs, err := g.Generate()
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+
+	assert.Equal(t, "Montréal", g.CLDR.Locales["fr_CA"].Extra["team"])
+
+	// the plugin template is formatted
+	data, err := os.ReadFile(out + "/fr_CA/team.go")
+	assert.NoError(t, err)
+	assert.Equal(t, "package fr_CA\n\nconst Team = \"Montréal\"\n", string(data))
+
+	data, err = os.ReadFile(out + "/root/team.go")
+	assert.NoError(t, err)
+	assert.Equal(t, "package root\n\nconst Team = \"\"\n", string(data))
+
+	data, err = os.ReadFile(out + "/teams.go")
+	assert.NoError(t, err)
+	assert.Equal(t, "package locales\n\nvar Teams = map[string]string{\n\t\"fr\":    \"Paris\",\n\t\"fr_CA\": \"Montréal\",\n}\n", string(data))
+
+	for _, file := range []string{"/fr/fr.go", "/fr_CA/fr_CA.go", "/locales.go", "/all/all.go"} {
+		assert.FileExists(t, out+file)
+	}
+}
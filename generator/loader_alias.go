@@ -0,0 +1,394 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// XPathSegment is an element of a path with its distinguishing attributes:
+// monthWidth[@type='abbreviated']
+type XPathSegment struct {
+	Name  string
+	Attrs map[string]string
+}
+
+// XPath is a path from the ldml element, ie: dates/calendars/calendar[@type='gregorian']
+type XPath []XPathSegment
+
+var xpathPredicate = regexp.MustCompile(`\[@([\w:-]+)=['"]([^'"]*)['"]\]`)
+
+// the attributes not used to identify an element
+var nonDistinguishingAttrs = []string{"draft", "references"}
+
+func (p XPath) String() string {
+	parts := []string{}
+	for _, s := range p {
+		part := s.Name
+
+		names := []string{}
+		for name := range s.Attrs {
+			names = append(names, name)
+		}
+
+		slices.Sort(names)
+
+		for _, name := range names {
+			part += fmt.Sprintf("[@%s='%s']", name, s.Attrs[name])
+		}
+
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// Resolve returns the path targeted by the alias path, relative to the
+// current path: ../currencyFormat[@type='standard']. An absolute path starts
+// with //ldml.
+func (p XPath) Resolve(path string) (XPath, error) {
+	resolved := slices.Clone(p)
+
+	if strings.HasPrefix(path, "//ldml") {
+		resolved = XPath{}
+		path = strings.TrimPrefix(strings.TrimPrefix(path, "//ldml"), "/")
+	}
+
+	for _, part := range splitXPath(path) {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if len(resolved) == 0 {
+				return nil, fmt.Errorf("the alias path %q goes above the ldml element", path)
+			}
+
+			resolved = resolved[:len(resolved)-1]
+		default:
+			segment := XPathSegment{Attrs: map[string]string{}}
+			segment.Name, _, _ = strings.Cut(part, "[")
+
+			for _, m := range xpathPredicate.FindAllStringSubmatch(part, -1) {
+				segment.Attrs[m[1]] = m[2]
+			}
+
+			resolved = append(resolved, segment)
+		}
+	}
+
+	return resolved, nil
+}
+
+// splitXPath splits the path on the slashes outside the predicates.
+func splitXPath(path string) []string {
+	parts := []string{}
+
+	depth, start := 0, 0
+	for i, c := range path {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '/':
+			if depth == 0 {
+				parts = append(parts, path[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, path[start:])
+}
+
+// XmlNode is a generic element of a LDML document, used to resolve the
+// aliases before the document is decoded into the Ldml struct.
+type XmlNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Text    string     `xml:",chardata"`
+	Nodes   []*XmlNode `xml:",any"`
+}
+
+func (n *XmlNode) GetAttr(name string) string {
+	for _, attr := range n.Attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// Segment returns the path segment identifying the node.
+func (n *XmlNode) Segment() XPathSegment {
+	segment := XPathSegment{Name: n.XMLName.Local, Attrs: map[string]string{}}
+
+	for _, attr := range n.Attrs {
+		if !slices.Contains(nonDistinguishingAttrs, attr.Name.Local) {
+			segment.Attrs[attr.Name.Local] = attr.Value
+		}
+	}
+
+	return segment
+}
+
+func (n *XmlNode) match(segment XPathSegment) bool {
+	s := n.Segment()
+	if s.Name != segment.Name || len(s.Attrs) != len(segment.Attrs) {
+		return false
+	}
+
+	for name, value := range segment.Attrs {
+		if s.Attrs[name] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsEmpty returns true if the node has no value, an alias is not a value.
+func (n *XmlNode) IsEmpty() bool {
+	for _, c := range n.Nodes {
+		if c.XMLName.Local != "alias" {
+			return false
+		}
+	}
+
+	return strings.TrimSpace(n.Text) == ""
+}
+
+// Find returns the node at the path relative to the node, or nil.
+func (n *XmlNode) Find(path XPath) *XmlNode {
+	node := n
+
+	for _, segment := range path {
+		var found *XmlNode
+		for _, c := range node.Nodes {
+			if c.match(segment) {
+				found = c
+				break
+			}
+		}
+
+		if found == nil {
+			return nil
+		}
+
+		node = found
+	}
+
+	return node
+}
+
+// Ensure returns the node at the path, the missing nodes are created.
+func (n *XmlNode) Ensure(path XPath) *XmlNode {
+	node := n
+
+	for _, segment := range path {
+		var found *XmlNode
+		for _, c := range node.Nodes {
+			if c.match(segment) {
+				found = c
+				break
+			}
+		}
+
+		if found == nil {
+			found = &XmlNode{XMLName: xml.Name{Local: segment.Name}}
+			for name, value := range segment.Attrs {
+				found.Attrs = append(found.Attrs, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+			}
+
+			node.Nodes = append(node.Nodes, found)
+		}
+
+		node = found
+	}
+
+	return node
+}
+
+// Copy returns a deep copy of the node, without the aliases.
+func (n *XmlNode) Copy() *XmlNode {
+	c := &XmlNode{
+		XMLName: n.XMLName,
+		Attrs:   slices.Clone(n.Attrs),
+		Text:    n.Text,
+	}
+
+	for _, child := range n.Nodes {
+		if child.XMLName.Local == "alias" {
+			continue
+		}
+
+		c.Nodes = append(c.Nodes, child.Copy())
+	}
+
+	return c
+}
+
+// merge adds the values of the other node missing in the node, the nodes
+// follow the order of the other node as the labels are read by position.
+// It returns true if a value has been added.
+func (n *XmlNode) merge(other *XmlNode) bool {
+	changed := false
+
+	if len(n.Nodes) == 0 && strings.TrimSpace(n.Text) == "" && strings.TrimSpace(other.Text) != "" {
+		n.Text = other.Text
+		changed = true
+	}
+
+	nodes := []*XmlNode{}
+	for _, o := range other.Nodes {
+		if o.XMLName.Local == "alias" {
+			continue
+		}
+
+		var found *XmlNode
+		for _, c := range n.Nodes {
+			if c.match(o.Segment()) {
+				found = c
+				break
+			}
+		}
+
+		if found == nil {
+			found = o.Copy()
+			changed = true
+		} else {
+			changed = found.merge(o) || changed
+		}
+
+		nodes = append(nodes, found)
+	}
+
+	for _, c := range n.Nodes {
+		if !slices.Contains(nodes, c) {
+			nodes = append(nodes, c)
+		}
+	}
+
+	n.Nodes = nodes
+
+	return changed
+}
+
+// Alias is an alias element: the Source node inherits the values of the
+// Target node, resolved from the locale being loaded.
+type Alias struct {
+	Source XPath
+	Target XPath
+}
+
+// GetAliases returns the aliases defined in the document.
+func (n *XmlNode) GetAliases() ([]*Alias, error) {
+	aliases := []*Alias{}
+
+	var walk func(node *XmlNode, path XPath) error
+	walk = func(node *XmlNode, path XPath) error {
+		for _, c := range node.Nodes {
+			if c.XMLName.Local != "alias" {
+				if err := walk(c, append(slices.Clone(path), c.Segment())); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			target, err := path.Resolve(c.GetAttr("path"))
+			if err != nil {
+				return err
+			}
+
+			aliases = append(aliases, &Alias{Source: path, Target: target})
+		}
+
+		return nil
+	}
+
+	return aliases, walk(n, XPath{})
+}
+
+// IsRootDocument returns true if the document is the root locale.
+func (n *XmlNode) IsRootDocument() bool {
+	language := n.Find(XPath{{Name: "identity", Attrs: map[string]string{}}, {Name: "language", Attrs: map[string]string{"type": "root"}}})
+
+	return language != nil
+}
+
+// ResolveAliases returns a copy of the document where the aliases defined
+// in the document and its parents are materialized: the values of the
+// alias target, found in the document, are copied to the alias source. The
+// parents are ordered from the closest one to root. An alias source defined
+// in a parent, root excepted, is not materialized as the generated locale
+// inherits the parent value, this is how ICU resolves the values. The keep
+// function can skip some aliases.
+// http://www.unicode.org/reports/tr35/#Alias_Elements
+func ResolveAliases(doc *XmlNode, parents []*XmlNode, keep func(alias *Alias) bool) (*XmlNode, error) {
+	aliases := []*Alias{}
+	seen := map[string]bool{}
+
+	for _, d := range append([]*XmlNode{doc}, parents...) {
+		found, err := d.GetAliases()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range found {
+			if key := a.Source.String(); !seen[key] {
+				seen[key] = true
+				aliases = append(aliases, a)
+			}
+		}
+	}
+
+	resolved := doc.Copy()
+
+	// an alias target can be the source of another alias, so the aliases
+	// are resolved until nothing changes
+	for changed := true; changed; {
+		changed = false
+
+		for _, a := range aliases {
+			if keep != nil && !keep(a) {
+				continue
+			}
+
+			if definedInParents(a.Source, parents) {
+				continue
+			}
+
+			target := resolved.Find(a.Target)
+			if target == nil || target.IsEmpty() {
+				continue
+			}
+
+			changed = resolved.Ensure(a.Source).merge(target) || changed
+		}
+	}
+
+	return resolved, nil
+}
+
+func definedInParents(path XPath, parents []*XmlNode) bool {
+	for _, p := range parents {
+		if p.IsRootDocument() {
+			continue
+		}
+
+		if node := p.Find(path); node != nil && !node.IsEmpty() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,135 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var aliasRoot = `<ldml>
+	<identity><language type="root"/></identity>
+	<dates><calendars><calendar type="gregorian">
+		<months>
+			<monthContext type="format">
+				<monthWidth type="abbreviated"><alias source="locale" path="../monthWidth[@type='wide']"/></monthWidth>
+				<monthWidth type="wide"><month type="1">M01</month><month type="2">M02</month></monthWidth>
+			</monthContext>
+			<monthContext type="stand-alone">
+				<monthWidth type="abbreviated"><alias source="locale" path="../../monthContext[@type='format']/monthWidth[@type='abbreviated']"/></monthWidth>
+			</monthContext>
+		</months>
+	</calendar></calendars></dates>
+	<numbers>
+		<defaultNumberingSystem>latn</defaultNumberingSystem>
+		<currencyFormats numberSystem="arab"><alias source="locale" path="../currencyFormats[@numberSystem='latn']"/></currencyFormats>
+		<currencyFormats numberSystem="latn">
+			<currencyFormatLength>
+				<currencyFormat type="standard"><pattern>¤#,##0.00</pattern></currencyFormat>
+				<currencyFormat type="accounting"><alias source="locale" path="../currencyFormat[@type='standard']"/></currencyFormat>
+			</currencyFormatLength>
+		</currencyFormats>
+	</numbers>
+</ldml>`
+
+func loadAliasDocument(t *testing.T, data string) *XmlNode {
+	doc := &XmlNode{}
+	assert.Nil(t, xml.Unmarshal([]byte(data), doc))
+
+	return doc
+}
+
+func resolveAliasDocument(t *testing.T, doc *XmlNode, parents []*XmlNode) *Ldml {
+	resolved, err := ResolveAliases(doc, parents, GetAliasFilter(doc, parents))
+	assert.Nil(t, err)
+
+	data, err := xml.Marshal(resolved)
+	assert.Nil(t, err)
+
+	ldml := &Ldml{}
+	assert.Nil(t, xml.Unmarshal(data, ldml))
+
+	return ldml
+}
+
+func Test_XPath_Resolve(t *testing.T) {
+	path, err := XPath{}.Resolve("numbers/currencyFormats[@numberSystem='latn']/currencyFormatLength")
+	assert.Nil(t, err)
+	assert.Equal(t, "numbers/currencyFormats[@numberSystem='latn']/currencyFormatLength", path.String())
+
+	path, err = path.Resolve("../../decimalFormats[@numberSystem=\"arab\"][@alt='x/y']")
+	assert.Nil(t, err)
+	assert.Equal(t, "numbers/decimalFormats[@alt='x/y'][@numberSystem='arab']", path.String())
+
+	path, err = path.Resolve("//ldml/dates")
+	assert.Nil(t, err)
+	assert.Equal(t, "dates", path.String())
+
+	_, err = path.Resolve("../..")
+	assert.NotNil(t, err)
+}
+
+func Test_Resolve_Aliases_Root(t *testing.T) {
+	ldml := resolveAliasDocument(t, loadAliasDocument(t, aliasRoot), nil)
+
+	months := ldml.Dates.Calendars.Calendar[0].Months.MonthContext
+	assert.Equal(t, "abbreviated", months[1].MonthWidth[0].Type)
+	assert.Equal(t, "M02", months[1].MonthWidth[0].Month[1].Text)
+
+	// the arab formats are not used by root
+	assert.Len(t, ldml.Numbers.CurrencyFormats, 2)
+	assert.Empty(t, ldml.Numbers.CurrencyFormats[0].CurrencyFormatLength)
+
+	formats := ldml.Numbers.CurrencyFormats[1].CurrencyFormatLength[0].CurrencyFormat
+	assert.Equal(t, "accounting", formats[1].Type)
+	assert.Equal(t, "¤#,##0.00", formats[1].Pattern[0].Text)
+}
+
+func Test_Resolve_Aliases_Locale(t *testing.T) {
+	root := loadAliasDocument(t, aliasRoot)
+
+	fr := loadAliasDocument(t, `<ldml>
+		<identity><language type="fr"/></identity>
+		<dates><calendars><calendar type="gregorian"><months>
+			<monthContext type="format">
+				<monthWidth type="abbreviated"><month type="1">janv.</month><month type="2">févr.</month></monthWidth>
+				<monthWidth type="wide"><month type="1">janvier</month><month type="2">février</month></monthWidth>
+			</monthContext>
+		</months></calendar></calendars></dates>
+		<numbers>
+			<defaultNumberingSystem>arab</defaultNumberingSystem>
+			<currencyFormats numberSystem="latn">
+				<currencyFormatLength>
+					<currencyFormat type="standard"><pattern>#,##0.00 ¤</pattern></currencyFormat>
+				</currencyFormatLength>
+			</currencyFormats>
+		</numbers>
+	</ldml>`)
+
+	ldml := resolveAliasDocument(t, fr, []*XmlNode{root})
+
+	// the stand-alone months use the format months of the locale
+	months := ldml.Dates.Calendars.Calendar[0].Months.MonthContext
+	assert.Equal(t, "stand-alone", months[1].Type)
+	assert.Equal(t, "févr.", months[1].MonthWidth[0].Month[1].Text)
+
+	// arab is used, so the formats are materialized from the latn ones
+	assert.Len(t, ldml.Numbers.CurrencyFormats, 2)
+	assert.Equal(t, "arab", ldml.Numbers.CurrencyFormats[1].NumberSystem)
+
+	formats := ldml.Numbers.CurrencyFormats[1].CurrencyFormatLength[0].CurrencyFormat
+	assert.Equal(t, "#,##0.00 ¤", formats[0].Pattern[0].Text)
+	assert.Equal(t, "accounting", formats[1].Type)
+	assert.Equal(t, "#,##0.00 ¤", formats[1].Pattern[0].Text)
+
+	// fr_CA inherits the stand-alone months materialized in fr
+	frCA := loadAliasDocument(t, `<ldml><identity><language type="fr"/><territory type="CA"/></identity></ldml>`)
+	ldml = resolveAliasDocument(t, frCA, []*XmlNode{fr, root})
+
+	assert.Empty(t, ldml.Dates.Calendars.Calendar)
+}
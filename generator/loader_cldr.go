@@ -3,15 +3,26 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import (
+	"archive/zip"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"io/fs"
+	"os"
 )
 
 type CLDR struct {
-	Path        string
+	// the CLDR common folder or the zip archive, ie: data/core.zip
+	Path string
+	// the files of the common folder: main/fr.xml, supplemental/plurals.xml
+	FS fs.FS
+	// closes the zip archive
+	closer io.Closer
+	// the CLDR release, ie: 44
+	Version     string
 	Validities  []*Validity
 	RootLocale  *Locale
 	MetaZones   []*MetaZone
@@ -19,18 +30,126 @@ type CLDR struct {
 	Territories map[string]*Territory
 	Currencies  map[string]*Currency
 	DayPeriods  map[string][]*DayPeriodRule
+	Plurals     map[string][]*PluralRule
+	// time zone => CLDR time zone, ie: Asia/Kolkata => Asia/Calcutta
+	ZoneAliases map[string]string
+	// CLDR time zone => short BCP 47 id, ie: Europe/Paris => frpar
+	ZoneIds map[string]string
+	// territory => week data, 001 is the default value
+	WeekData map[string]*WeekData
+	// tag => likely tag, ie: fr => fr_Latn_FR
+	LikelySubtags map[string]string
+	// territory => calendars by preference, 001 is the default value
+	CalendarPreferences map[string][]string
+	// territory => contained territories, ie: 419 => 013 029 005
+	TerritoryContainment map[string][]string
+	// the language matching data, the variables are not expanded
+	LanguageMatches []*LanguageMatch
+	MatchVariables  map[string]string
+	ParadigmLocales []string
+	// locale => parent locale, when the parent is not the truncated code:
+	// en_NL => en_150
+	ParentLocales map[string]string
+	// locale => coverage level, ie: fr => modern, only loaded when the
+	// locales are selected by coverage level
+	CoverageLevels map[string]string
+	// the minimal draft status of the values, see DraftLevels
+	DraftLevel string
+	// the alt variants of the display names to keep, ie: short, variant;
+	// all the variants are kept if empty
+	Alts []string
+	// the handlers of the plugins, called after the built-in ones
+	LdmlHandlers []LdmlHandler
+	// the data attached by the plugins
+	Extra map[string]interface{}
 }
 
-func LoadCLDR(CldrPath string) *CLDR {
-	cldr := &CLDR{}
+// OpenCLDRFS returns the files of the CLDR common folder, the path is the
+// common folder or a zip archive like core.zip. The common folder of the
+// archive is used if it exists. The closer is nil for a folder.
+func OpenCLDRFS(path string) (fs.FS, io.Closer, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if info.IsDir() {
+		return os.DirFS(path), nil, nil
+	}
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to open the archive %s: %w", path, err)
+	}
+
+	if info, err := fs.Stat(archive, "common"); err == nil && info.IsDir() {
+		common, err := fs.Sub(archive, "common")
+		if err != nil {
+			archive.Close()
+			return nil, nil, err
+		}
+
+		return common, archive, nil
+	}
+
+	return archive, archive, nil
+}
+
+// LoadCLDR loads the CLDR from the common folder or from a zip archive, the
+// CLDR must be closed to release the archive.
+func LoadCLDR(CldrPath string) (*CLDR, error) {
+	fsys, closer, err := OpenCLDRFS(CldrPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cldr, err := LoadCLDRFS(fsys)
+	if err != nil {
+		if closer != nil {
+			closer.Close()
+		}
+
+		return nil, err
+	}
+
 	cldr.Path = CldrPath
+	cldr.closer = closer
+
+	return cldr, nil
+}
+
+// Close releases the zip archive of the CLDR files.
+func (cldr *CLDR) Close() error {
+	if cldr.closer == nil {
+		return nil
+	}
+
+	return cldr.closer.Close()
+}
+
+// LoadCLDRFS loads the supplemental and validity files of the CLDR files,
+// the locales are loaded on demand.
+func LoadCLDRFS(fsys fs.FS) (*CLDR, error) {
+	cldr := &CLDR{}
+	cldr.FS = fsys
 	cldr.Locales = map[string]*Locale{}
 	cldr.Territories = map[string]*Territory{}
 	cldr.Currencies = map[string]*Currency{}
 	cldr.DayPeriods = map[string][]*DayPeriodRule{}
+	cldr.Plurals = map[string][]*PluralRule{}
+	cldr.WeekData = map[string]*WeekData{}
+	cldr.LikelySubtags = map[string]string{}
+	cldr.CalendarPreferences = map[string][]string{}
+	cldr.TerritoryContainment = map[string][]string{}
+	cldr.MatchVariables = map[string]string{}
+	cldr.ParentLocales = map[string]string{}
+	cldr.ZoneAliases = map[string]string{}
+	cldr.ZoneIds = map[string]string{}
+	cldr.DraftLevel = DefaultDraftLevel
+	cldr.Extra = map[string]interface{}{}
 
 	// load validity files
-	validityFiles := map[string]func(cldr *CLDR, supplemental *SupplementalData){
+	validityFiles := map[string]SupplementalHandler{
 		"currency.xml": AttachValidity,
 		"language.xml": AttachValidity,
 		"region.xml":   AttachValidity,
@@ -44,16 +163,13 @@ func LoadCLDR(CldrPath string) *CLDR {
 	for file, handler := range validityFiles {
 		fmt.Printf(" > Loading validity file: %s\n", file)
 
-		supplemental := &SupplementalData{}
-		if err := LoadXml(CldrPath+"/validity/"+file, supplemental); err != nil {
-			log.Panic(err.Error())
+		if err := cldr.LoadSupplemental("validity/"+file, handler); err != nil {
+			return nil, fmt.Errorf("unable to load the validity file %s: %w", file, err)
 		}
-
-		handler(cldr, supplemental)
 	}
 
 	// load supplemental files
-	supplementalFiles := map[string]func(cldr *CLDR, supplemental *SupplementalData){
+	supplementalFiles := map[string]SupplementalHandler{
 		// "attributeValueValidity.xml",
 		// "characters.xml",
 		// "coverageLevels.xml",
@@ -61,13 +177,13 @@ func LoadCLDR(CldrPath string) *CLDR {
 		// "genderList.xml",
 		// "grammaticalFeatures.xml",
 		// "languageGroup.xml",
-		// "languageInfo.xml",
-		// "likelySubtags.xml",
-		"metaZones.xml": AttachMetaZones,
+		"languageInfo.xml":  AttachLanguageMatching,
+		"likelySubtags.xml": AttachLikelySubtags,
+		"metaZones.xml":     AttachMetaZones,
 		// "numberingSystems.xml",
 		// "ordinals.xml",
 		// "pluralRanges.xml",
-		// "plurals.xml",
+		"plurals.xml": AttachPluralRules,
 		// "rgScope.xml",
 		// "subdivisions.xml",
 		"supplementalData.xml": AttachSupplementalData,
@@ -79,15 +195,36 @@ func LoadCLDR(CldrPath string) *CLDR {
 	for file, handler := range supplementalFiles {
 		fmt.Printf(" > Loading supplemental file: %s\n", file)
 
-		supplemental := &SupplementalData{}
-		if err := LoadXml(CldrPath+"/supplemental/"+file, supplemental); err != nil {
-			log.Panic(err.Error())
+		if err := cldr.LoadSupplemental("supplemental/"+file, handler); err != nil {
+			return nil, fmt.Errorf("unable to load the supplemental file %s: %w", file, err)
 		}
+	}
 
-		handler(cldr, supplemental)
+	// the time zone aliases are optional, the names of the renamed zones
+	// are not found without them
+	fmt.Printf(" > Loading bcp47 file: timezone.xml\n")
+
+	bcp47 := &LdmlBcp47{}
+	if err := LoadXml(cldr.FS, "bcp47/timezone.xml", bcp47); err == nil {
+		if err := AttachZoneAliases(cldr, bcp47); err != nil {
+			return nil, fmt.Errorf("unable to load the bcp47 file timezone.xml: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("unable to load the bcp47 file timezone.xml: %w", err)
 	}
 
-	return cldr
+	return cldr, nil
+}
+
+// LoadSupplemental decodes a file of the CLDR files, ie:
+// supplemental/plurals.xml, and calls the handler.
+func (cldr *CLDR) LoadSupplemental(name string, handler SupplementalHandler) error {
+	supplemental := &SupplementalData{}
+	if err := LoadXml(cldr.FS, name, supplemental); err != nil {
+		return err
+	}
+
+	return handler(cldr, supplemental)
 }
 
 func (cldr *CLDR) GetValidity(code, status string) *Validity {
@@ -99,3 +236,41 @@ func (cldr *CLDR) GetValidity(code, status string) *Validity {
 
 	return nil
 }
+
+// GetLocale returns the locale loaded with its parents, as a locale is
+// loaded after its parent: en_NL requires en_150, en_001 and en. The loaded
+// function is called for each locale loaded, the parents first.
+func (cldr *CLDR) GetLocale(code string, loaded func(locale *Locale)) (*Locale, error) {
+	if code == "root" {
+		if cldr.RootLocale == nil {
+			locale, err := LoadLocaleFromFile("main/root.xml", cldr)
+			if err != nil {
+				return nil, fmt.Errorf("unable to load the locale root: %w", err)
+			}
+
+			cldr.RootLocale = locale
+			loaded(cldr.RootLocale)
+		}
+
+		return cldr.RootLocale, nil
+	}
+
+	if locale, ok := cldr.Locales[code]; ok {
+		return locale, nil
+	}
+
+	if _, err := cldr.GetLocale(cldr.GetParentCode(code), loaded); err != nil {
+		return nil, err
+	}
+
+	locale, err := LoadLocaleFromFile("main/"+code+".xml", cldr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the locale %s: %w", code, err)
+	}
+
+	cldr.Locales[locale.Code] = locale
+
+	loaded(locale)
+
+	return locale, nil
+}
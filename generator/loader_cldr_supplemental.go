@@ -3,10 +3,13 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import (
 	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -16,6 +19,45 @@ type MetaZone struct {
 	Zone string
 }
 
+// PluralRule is a cardinal plural rule, the Rule contains only the
+// condition, the samples (@integer, @decimal) are stripped.
+type PluralRule struct {
+	Count string
+	Rule  string
+}
+
+// WeekData defines the weeks of a territory, the days are the
+// time.Weekday values: 0 is sunday.
+type WeekData struct {
+	FirstDay     int
+	MinDays      int
+	WeekendStart int
+	WeekendEnd   int
+}
+
+var dtdVersion = regexp.MustCompile(`<!ATTLIST\s+version\s+cldrVersion\s+CDATA\s+#FIXED\s+"([^"]+)"`)
+
+// AttachVersion loads the CLDR release, the cldrVersion attribute has a
+// fixed value declared in the DTD, so the DTD is read if the attribute is
+// not set in the file.
+func AttachVersion(cldr *CLDR, supplemental *SupplementalData) error {
+	if cldr.Version = supplemental.Version.CldrVersion; cldr.Version != "" {
+		return nil
+	}
+
+	data, err := fs.ReadFile(cldr.FS, "dtd/ldmlSupplemental.dtd")
+	if err != nil {
+		fmt.Printf("Unable to find the CLDR version: %s\n", err)
+		return nil
+	}
+
+	if m := dtdVersion.FindSubmatch(data); m != nil {
+		cldr.Version = string(m[1])
+	}
+
+	return nil
+}
+
 type DayPeriodRule struct {
 	Type   string
 	From   int
@@ -23,8 +65,12 @@ type DayPeriodRule struct {
 	At     int
 }
 
-func AttachMetaZones(cldr *CLDR, supplemental *SupplementalData) {
+func AttachMetaZones(cldr *CLDR, supplemental *SupplementalData) error {
 	for _, t := range supplemental.MetaZones.MetazoneInfo.Timezone {
+		if len(t.UsesMetazone) == 0 {
+			return fmt.Errorf("the time zone %s has no metazone", t.Type)
+		}
+
 		last := t.UsesMetazone[len(t.UsesMetazone)-1]
 
 		meta := &MetaZone{
@@ -34,9 +80,23 @@ func AttachMetaZones(cldr *CLDR, supplemental *SupplementalData) {
 
 		cldr.MetaZones = append(cldr.MetaZones, meta)
 	}
+
+	return nil
 }
 
-func AttachSupplementalData(cldr *CLDR, supplemental *SupplementalData) {
+func AttachSupplementalData(cldr *CLDR, supplemental *SupplementalData) error {
+	for _, attach := range []SupplementalHandler{
+		AttachVersion,
+		AttachWeekData,
+		AttachCalendarPreferences,
+		AttachTerritoryContainment,
+		AttachParentLocales,
+	} {
+		if err := attach(cldr, supplemental); err != nil {
+			return err
+		}
+	}
+
 	for _, i := range supplemental.CurrencyData.Fractions.Info {
 		if i.Iso4217 == "DEFAULT" {
 			continue
@@ -75,9 +135,11 @@ func AttachSupplementalData(cldr *CLDR, supplemental *SupplementalData) {
 			cldr.Currencies[i.Type].Numeric = fmt.Sprintf("%03d", v)
 		}
 	}
+
+	return nil
 }
 
-func AttachDayPeriodRules(cldr *CLDR, supplemental *SupplementalData) {
+func AttachDayPeriodRules(cldr *CLDR, supplemental *SupplementalData) error {
 	for _, s := range supplemental.DayPeriodRuleSet {
 		if s.Type == "selection" {
 			fmt.Printf("Skip selection rule: %s\n", s.Type)
@@ -140,4 +202,319 @@ func AttachDayPeriodRules(cldr *CLDR, supplemental *SupplementalData) {
 			}
 		}
 	}
+
+	return nil
+}
+
+func AttachPluralRules(cldr *CLDR, supplemental *SupplementalData) error {
+	for _, p := range supplemental.Plurals {
+		// ordinals are stored in a dedicated file, but the type is
+		// also used to mark them
+		if p.Type != "" && p.Type != "cardinal" {
+			continue
+		}
+
+		for _, r := range p.PluralRules {
+			rules := []*PluralRule{}
+			for _, rule := range r.PluralRule {
+				condition := rule.Text
+				if i := strings.Index(condition, "@"); i != -1 {
+					condition = condition[:i]
+				}
+
+				rules = append(rules, &PluralRule{
+					Count: rule.Count,
+					Rule:  strings.TrimSpace(condition),
+				})
+			}
+
+			for _, locale := range strings.Split(r.Locales, " ") {
+				if locale == "" {
+					continue
+				}
+
+				cldr.Plurals[locale] = rules
+			}
+		}
+	}
+
+	return nil
+}
+
+var weekDays = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+
+// AttachWeekData loads the week data per territory, the values not defined
+// for a territory are the ones of the world (001).
+func AttachWeekData(cldr *CLDR, supplemental *SupplementalData) error {
+	get := func(territory string) *WeekData {
+		if _, ok := cldr.WeekData[territory]; !ok {
+			cldr.WeekData[territory] = &WeekData{FirstDay: -1, MinDays: -1, WeekendStart: -1, WeekendEnd: -1}
+		}
+
+		return cldr.WeekData[territory]
+	}
+
+	data := supplemental.WeekData
+
+	for _, d := range data.MinDays {
+		for _, territory := range strings.Fields(d.Territories) {
+			minDays, err := ifEmptyInt(d.Count, "1")
+			if err != nil {
+				return fmt.Errorf("invalid minDays count for %s: %w", territory, err)
+			}
+
+			get(territory).MinDays = minDays
+		}
+	}
+
+	for _, d := range data.FirstDay {
+		// the alt values are variants used by some applications
+		if d.Alt != "" {
+			continue
+		}
+
+		for _, territory := range strings.Fields(d.Territories) {
+			get(territory).FirstDay = weekDays[d.Day]
+		}
+	}
+
+	for _, d := range data.WeekendStart {
+		for _, territory := range strings.Fields(d.Territories) {
+			get(territory).WeekendStart = weekDays[d.Day]
+		}
+	}
+
+	for _, d := range data.WeekendEnd {
+		for _, territory := range strings.Fields(d.Territories) {
+			get(territory).WeekendEnd = weekDays[d.Day]
+		}
+	}
+
+	// ISO 8601 values if the world is not defined
+	world := get("001")
+	for _, v := range []struct {
+		value *int
+		def   int
+	}{
+		{&world.FirstDay, 1},
+		{&world.MinDays, 4},
+		{&world.WeekendStart, 6},
+		{&world.WeekendEnd, 0},
+	} {
+		if *v.value == -1 {
+			*v.value = v.def
+		}
+	}
+
+	for _, d := range cldr.WeekData {
+		if d.FirstDay == -1 {
+			d.FirstDay = world.FirstDay
+		}
+
+		if d.MinDays == -1 {
+			d.MinDays = world.MinDays
+		}
+
+		if d.WeekendStart == -1 {
+			d.WeekendStart = world.WeekendStart
+		}
+
+		if d.WeekendEnd == -1 {
+			d.WeekendEnd = world.WeekendEnd
+		}
+	}
+
+	return nil
+}
+
+func AttachLikelySubtags(cldr *CLDR, supplemental *SupplementalData) error {
+	for _, l := range supplemental.LikelySubtags.LikelySubtag {
+		cldr.LikelySubtags[l.From] = l.To
+	}
+
+	return nil
+}
+
+// AttachCalendarPreferences loads the calendars used in a territory, by
+// order of preference: TH is "buddhist gregorian".
+func AttachCalendarPreferences(cldr *CLDR, supplemental *SupplementalData) error {
+	for _, p := range supplemental.CalendarPreferenceData.CalendarPreference {
+		for _, territory := range strings.Fields(p.Territories) {
+			cldr.CalendarPreferences[territory] = strings.Fields(p.Ordering)
+		}
+	}
+
+	return nil
+}
+
+// AttachTerritoryContainment loads the territories contained in a region,
+// the deprecated groups are skipped.
+func AttachTerritoryContainment(cldr *CLDR, supplemental *SupplementalData) error {
+	for _, g := range supplemental.TerritoryContainment.Group {
+		if g.Status == "deprecated" {
+			continue
+		}
+
+		cldr.TerritoryContainment[g.Type] = append(cldr.TerritoryContainment[g.Type], strings.Fields(g.Contains)...)
+	}
+
+	return nil
+}
+
+// LanguageMatch is the distance between a desired and a supported locale,
+// the fields are patterns: en_*_$enUS, with * matching any value and
+// $var (or $!var) the regions of a variable.
+type LanguageMatch struct {
+	Desired   string
+	Supported string
+	Distance  int
+	Oneway    bool
+}
+
+// AttachLanguageMatching loads the data used to compute the distance
+// between two locales, only the written_new matches are used.
+func AttachLanguageMatching(cldr *CLDR, supplemental *SupplementalData) error {
+	for _, matches := range supplemental.LanguageMatching.LanguageMatches {
+		if matches.Type != "written_new" {
+			continue
+		}
+
+		cldr.ParadigmLocales = strings.Fields(matches.ParadigmLocales.Locales)
+
+		for _, v := range matches.MatchVariable {
+			cldr.MatchVariables[strings.TrimPrefix(v.ID, "$")] = v.Value
+		}
+
+		for _, m := range matches.LanguageMatch {
+			distance, _ := strconv.Atoi(m.Distance)
+
+			cldr.LanguageMatches = append(cldr.LanguageMatches, &LanguageMatch{
+				Desired:   m.Desired,
+				Supported: m.Supported,
+				Distance:  distance,
+				Oneway:    m.Oneway == "true",
+			})
+		}
+	}
+
+	return nil
+}
+
+// GetContainedTerritories returns the territories contained in the region,
+// including the intermediate regions, and the regions having all their
+// territories contained: 019 (Americas) contains 419 (Latin America).
+func (cldr *CLDR) GetContainedTerritories(region string) []string {
+	contained := map[string]bool{}
+
+	var walk func(code string)
+	walk = func(code string) {
+		for _, c := range cldr.TerritoryContainment[code] {
+			if !contained[c] {
+				contained[c] = true
+				walk(c)
+			}
+		}
+	}
+
+	walk(region)
+
+	for changed := true; changed; {
+		changed = false
+
+		for group, codes := range cldr.TerritoryContainment {
+			if contained[group] || group == region || len(codes) == 0 {
+				continue
+			}
+
+			all := true
+			for _, c := range codes {
+				all = all && contained[c]
+			}
+
+			if all {
+				contained[group], changed = true, true
+			}
+		}
+	}
+
+	territories := make([]string, 0, len(contained))
+	for c := range contained {
+		territories = append(territories, c)
+	}
+
+	sort.Strings(territories)
+
+	return territories
+}
+
+// GetMatchVariables returns the regions of the language matching
+// variables, the value is a list of regions joined by + (or - to remove
+// the regions), a macro region is replaced by its territories.
+func (cldr *CLDR) GetMatchVariables() map[string][]string {
+	variables := map[string][]string{}
+
+	for name, value := range cldr.MatchVariables {
+		regions := map[string]bool{}
+
+		op, start := '+', 0
+		for i, c := range value + "+" {
+			if c != '+' && c != '-' {
+				continue
+			}
+
+			if code := value[start:i]; code != "" {
+				for _, code := range append([]string{code}, cldr.GetContainedTerritories(code)...) {
+					regions[code] = op == '+'
+				}
+			}
+
+			op, start = c, i+1
+		}
+
+		list := []string{}
+		for code, ok := range regions {
+			if ok {
+				list = append(list, code)
+			}
+		}
+
+		sort.Strings(list)
+		variables[name] = list
+	}
+
+	return variables
+}
+
+// AttachParentLocales loads the parent of the locales not inheriting from
+// their truncated code, the parents specific to a component (collations,
+// segmentations, ...) are skipped.
+func AttachParentLocales(cldr *CLDR, supplemental *SupplementalData) error {
+	for _, parents := range supplemental.ParentLocales {
+		if parents.Component != "" {
+			continue
+		}
+
+		for _, p := range parents.ParentLocale {
+			for _, locale := range strings.Fields(p.Locales) {
+				cldr.ParentLocales[locale] = p.Parent
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetParentCode returns the code of the parent locale: the one defined by
+// the parentLocales data, or the code without its last subtag. The parent
+// of a language is root.
+func (cldr *CLDR) GetParentCode(code string) string {
+	if parent, ok := cldr.ParentLocales[code]; ok {
+		return parent
+	}
+
+	if i := strings.LastIndex(code, "_"); i > 0 {
+		return code[:i]
+	}
+
+	return "root"
 }
@@ -0,0 +1,172 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Attach_Week_Data(t *testing.T) {
+	data := `<supplementalData><weekData>
+		<minDays count="1" territories="001 US"/>
+		<minDays count="4" territories="FR"/>
+		<firstDay day="mon" territories="001"/>
+		<firstDay day="sun" territories="US"/>
+		<firstDay day="sun" territories="FR" alt="variant"/>
+		<weekendStart day="sat" territories="001"/>
+		<weekendStart day="fri" territories="IR"/>
+		<weekendEnd day="sun" territories="001"/>
+		<weekendEnd day="fri" territories="IR"/>
+	</weekData></supplementalData>`
+
+	supplemental := &SupplementalData{}
+	assert.Nil(t, xml.Unmarshal([]byte(data), supplemental))
+
+	cldr := &CLDR{WeekData: map[string]*WeekData{}}
+	assert.NoError(t, AttachWeekData(cldr, supplemental))
+
+	assert.Equal(t, &WeekData{FirstDay: 1, MinDays: 1, WeekendStart: 6, WeekendEnd: 0}, cldr.WeekData["001"])
+	assert.Equal(t, &WeekData{FirstDay: 0, MinDays: 1, WeekendStart: 6, WeekendEnd: 0}, cldr.WeekData["US"])
+	// the alt value is skipped, the missing values are the world ones
+	assert.Equal(t, &WeekData{FirstDay: 1, MinDays: 4, WeekendStart: 6, WeekendEnd: 0}, cldr.WeekData["FR"])
+	assert.Equal(t, &WeekData{FirstDay: 1, MinDays: 1, WeekendStart: 5, WeekendEnd: 5}, cldr.WeekData["IR"])
+}
+
+func Test_Attach_Week_Data_Invalid_Count(t *testing.T) {
+	data := `<supplementalData><weekData>
+		<minDays count="x" territories="001"/>
+	</weekData></supplementalData>`
+
+	supplemental := &SupplementalData{}
+	assert.Nil(t, xml.Unmarshal([]byte(data), supplemental))
+
+	cldr := &CLDR{WeekData: map[string]*WeekData{}}
+	assert.ErrorContains(t, AttachWeekData(cldr, supplemental), "invalid minDays count for 001")
+}
+
+func Test_Attach_Calendar_Preferences(t *testing.T) {
+	data := `<supplementalData><calendarPreferenceData>
+		<calendarPreference territories="001" ordering="gregorian"/>
+		<calendarPreference territories="TH" ordering="buddhist gregorian"/>
+		<calendarPreference territories="AF IR" ordering="persian gregorian islamic islamic-civil islamic-tbla"/>
+	</calendarPreferenceData></supplementalData>`
+
+	supplemental := &SupplementalData{}
+	assert.Nil(t, xml.Unmarshal([]byte(data), supplemental))
+
+	cldr := &CLDR{CalendarPreferences: map[string][]string{}}
+	assert.NoError(t, AttachCalendarPreferences(cldr, supplemental))
+
+	assert.Equal(t, []string{"gregorian"}, cldr.CalendarPreferences["001"])
+	assert.Equal(t, []string{"buddhist", "gregorian"}, cldr.CalendarPreferences["TH"])
+	assert.Equal(t, cldr.CalendarPreferences["AF"], cldr.CalendarPreferences["IR"])
+	assert.Len(t, cldr.CalendarPreferences["IR"], 5)
+}
+
+func Test_Attach_Language_Matching(t *testing.T) {
+	data := `<supplementalData>
+		<territoryContainment>
+			<group type="019" contains="021 005"/>
+			<group type="419" contains="005" grouping="true"/>
+			<group type="005" contains="AR BR"/>
+			<group type="021" contains="CA US"/>
+			<group type="QU" contains="FR" status="deprecated"/>
+		</territoryContainment>
+		<languageMatching>
+			<languageMatches type="written">
+				<languageMatch desired="no" supported="nb" distance="1"/>
+			</languageMatches>
+			<languageMatches type="written_new">
+				<paradigmLocales locales="en en_GB"/>
+				<matchVariable id="$americas" value="019-US"/>
+				<matchVariable id="$enUS" value="CA+US"/>
+				<languageMatch desired="ca" supported="es" distance="20" oneway="true"/>
+				<languageMatch desired="*" supported="*" distance="80"/>
+			</languageMatches>
+		</languageMatching>
+	</supplementalData>`
+
+	supplemental := &SupplementalData{}
+	assert.Nil(t, xml.Unmarshal([]byte(data), supplemental))
+
+	cldr := &CLDR{TerritoryContainment: map[string][]string{}, MatchVariables: map[string]string{}}
+	assert.NoError(t, AttachTerritoryContainment(cldr, supplemental))
+	assert.NoError(t, AttachLanguageMatching(cldr, supplemental))
+
+	assert.Equal(t, []string{"en", "en_GB"}, cldr.ParadigmLocales)
+	assert.Equal(t, []*LanguageMatch{
+		{Desired: "ca", Supported: "es", Distance: 20, Oneway: true},
+		{Desired: "*", Supported: "*", Distance: 80},
+	}, cldr.LanguageMatches)
+
+	// the grouping regions are included when all their territories are
+	assert.Equal(t, []string{"005", "021", "419", "AR", "BR", "CA", "US"}, cldr.GetContainedTerritories("019"))
+	assert.NotContains(t, cldr.TerritoryContainment, "QU")
+
+	variables := cldr.GetMatchVariables()
+	assert.Equal(t, []string{"CA", "US"}, variables["enUS"])
+	assert.Equal(t, []string{"005", "019", "021", "419", "AR", "BR", "CA"}, variables["americas"])
+}
+
+func Test_Attach_Parent_Locales(t *testing.T) {
+	data := `<supplementalData>
+		<parentLocales>
+			<parentLocale parent="root" locales="az_Arab sr_Latn"/>
+			<parentLocale parent="en_001" locales="en_150 en_AU"/>
+			<parentLocale parent="en_150" locales="en_NL"/>
+		</parentLocales>
+		<parentLocales component="segmentations">
+			<parentLocale parent="en" locales="en_NL"/>
+		</parentLocales>
+	</supplementalData>`
+
+	supplemental := &SupplementalData{}
+	assert.Nil(t, xml.Unmarshal([]byte(data), supplemental))
+
+	cldr := &CLDR{ParentLocales: map[string]string{}}
+	assert.NoError(t, AttachParentLocales(cldr, supplemental))
+
+	assert.Equal(t, "en_150", cldr.GetParentCode("en_NL"))
+	assert.Equal(t, "en_001", cldr.GetParentCode("en_150"))
+	assert.Equal(t, "en", cldr.GetParentCode("en_001"))
+	assert.Equal(t, "root", cldr.GetParentCode("en"))
+	assert.Equal(t, "root", cldr.GetParentCode("sr_Latn"))
+	assert.Equal(t, "fr", cldr.GetParentCode("fr_CA"))
+}
+
+func Test_Attach_Zone_Aliases(t *testing.T) {
+	data := `<ldmlBCP47><keyword>
+		<key name="ca"><type name="gregory"/></key>
+		<key name="tz">
+			<type name="frpar" alias="Europe/Paris"/>
+			<type name="inccu" alias="Asia/Calcutta Asia/Kolkata"/>
+			<type name="uaiev" alias="Europe/Kiev Europe/Kyiv" iana="Europe/Kyiv"/>
+			<type name="glgoh" alias="America/Godthab" iana="America/Nuuk"/>
+		</key>
+	</keyword></ldmlBCP47>`
+
+	bcp47 := &LdmlBcp47{}
+	assert.Nil(t, xml.Unmarshal([]byte(data), bcp47))
+
+	cldr := &CLDR{ZoneAliases: map[string]string{}, ZoneIds: map[string]string{}}
+	assert.NoError(t, AttachZoneAliases(cldr, bcp47))
+
+	assert.Equal(t, map[string]string{
+		"Asia/Kolkata": "Asia/Calcutta",
+		"Europe/Kyiv":  "Europe/Kiev",
+		"America/Nuuk": "America/Godthab",
+	}, cldr.ZoneAliases)
+
+	assert.Equal(t, map[string]string{
+		"Europe/Paris":    "frpar",
+		"Asia/Calcutta":   "inccu",
+		"Europe/Kiev":     "uaiev",
+		"America/Godthab": "glgoh",
+	}, cldr.ZoneIds)
+}
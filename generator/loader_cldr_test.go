@@ -0,0 +1,98 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"archive/zip"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Load_Xml(t *testing.T) {
+	fsys := fstest.MapFS{
+		"supplemental/supplementalData.xml": {Data: []byte(`<supplementalData><version cldrVersion="44"/></supplementalData>`)},
+	}
+
+	supplemental := &SupplementalData{}
+	assert.NoError(t, LoadXml(fsys, "supplemental/supplementalData.xml", supplemental))
+	assert.Equal(t, "44", supplemental.Version.CldrVersion)
+
+	assert.ErrorIs(t, LoadXml(fsys, "supplemental/plurals.xml", supplemental), fs.ErrNotExist)
+}
+
+func Test_Load_CLDR_Zip(t *testing.T) {
+	// the archive contains the common folder, like core.zip
+	cldr, err := LoadCLDR("fixtures/core.zip")
+	assert.NoError(t, err)
+
+	defer cldr.Close()
+
+	assert.Equal(t, "fixtures/core.zip", cldr.Path)
+	assert.Equal(t, "44", cldr.Version)
+	assert.Equal(t, "392", cldr.Currencies["JPY"].Numeric)
+
+	found, err := cldr.HasLocale("fr_CA")
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = cldr.HasLocale("de")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	codes, err := SelectLocales(cldr, &Selection{All: true, Coverage: "modern"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fr", "fr_CA"}, codes)
+
+	locale, err := cldr.GetLocale("fr_CA", func(locale *Locale) {})
+	assert.NoError(t, err)
+	assert.Equal(t, "y-MM-dd", locale.Calendars["gregorian"].Patterns["date_short"])
+	assert.Equal(t, "France", locale.Parent.Territories["FR"].Name)
+	assert.NotEmpty(t, locale.Parent.Annotations)
+}
+
+func Test_Open_CLDR_FS(t *testing.T) {
+	path := t.TempDir()
+
+	// an archive of the common folder content
+	f, err := os.Create(path + "/common.zip")
+	assert.NoError(t, err)
+
+	archive := zip.NewWriter(f)
+	w, err := archive.Create("main/root.xml")
+	assert.NoError(t, err)
+
+	_, err = w.Write([]byte("<ldml/>"))
+	assert.NoError(t, err)
+	assert.NoError(t, archive.Close())
+	assert.NoError(t, f.Close())
+
+	fsys, closer, err := OpenCLDRFS(path + "/common.zip")
+	assert.NoError(t, err)
+	assert.NotNil(t, closer)
+
+	data, err := fs.ReadFile(fsys, "main/root.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "<ldml/>", string(data))
+	assert.NoError(t, closer.Close())
+
+	// a folder
+	fsys, closer, err = OpenCLDRFS(path)
+	assert.NoError(t, err)
+	assert.Nil(t, closer)
+
+	_, err = fs.Stat(fsys, "common.zip")
+	assert.NoError(t, err)
+
+	_, _, err = OpenCLDRFS(path + "/core.zip")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = LoadCLDR(path + "/common.zip")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
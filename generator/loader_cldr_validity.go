@@ -3,7 +3,7 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import (
 	"strings"
@@ -15,7 +15,7 @@ type Validity struct {
 	Status string
 }
 
-func AttachValidity(cldr *CLDR, supplemental *SupplementalData) {
+func AttachValidity(cldr *CLDR, supplemental *SupplementalData) error {
 	for _, id := range supplemental.IdValidity.ID {
 		v := &Validity{
 			From:   id.Type,
@@ -25,6 +25,8 @@ func AttachValidity(cldr *CLDR, supplemental *SupplementalData) {
 
 		cldr.Validities = append(cldr.Validities, v)
 	}
+
+	return nil
 }
 
 func ParseValidityValues(message string) []string {
@@ -3,7 +3,7 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import (
 	"fmt"
@@ -21,9 +21,11 @@ type Currency struct {
 	CashRounding string
 	Numeric      string
 	Const        string
+	// alt => symbol, ie: narrow => $
+	Alts map[string]string
 }
 
-func AttachCurrencies(locale *Locale, cldr *CLDR, ldml *Ldml) {
+func AttachCurrencies(locale *Locale, cldr *CLDR, ldml *Ldml) error {
 	var currencies map[string]*Currency = map[string]*Currency{}
 
 	list := cldr.GetValidity("currency", "regular")
@@ -33,7 +35,7 @@ func AttachCurrencies(locale *Locale, cldr *CLDR, ldml *Ldml) {
 
 	if list == nil {
 		fmt.Printf("[%s] No currencies found\n", locale.Code)
-		return
+		return nil
 	}
 
 	if locale.IsRoot {
@@ -72,8 +74,13 @@ func AttachCurrencies(locale *Locale, cldr *CLDR, ldml *Ldml) {
 		}
 
 		symbol := ""
-		if len(t.Symbol) > 0 {
-			symbol = t.Symbol[0].Text
+		alts := map[string]string{}
+		for _, s := range t.Symbol {
+			if s.Alt == "" {
+				symbol = s.Text
+			} else if cldr.IsAltIncluded(s.Alt) {
+				alts[s.Alt] = s.Text
+			}
 		}
 
 		currencies[t.Type] = &Currency{
@@ -86,8 +93,11 @@ func AttachCurrencies(locale *Locale, cldr *CLDR, ldml *Ldml) {
 			CashRounding: ifEmptyString(cldr.Currencies[t.Type].CashRounding, "0"),
 			Numeric:      ifEmptyString(cldr.Currencies[t.Type].Numeric, "000"),
 			Const:        fmt.Sprintf("Currency_%s", strings.ToUpper(t.Type)),
+			Alts:         alts,
 		}
 	}
 
 	locale.Currencies = currencies
+
+	return nil
 }
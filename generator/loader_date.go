@@ -3,52 +3,69 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import (
 	"fmt"
 	"slices"
-	"strconv"
-	"strings"
 )
 
-type CalendarFormatter struct {
-	Comment string
-	Func    string
-}
-
 type Calendar struct {
 	// The calendar type
-	System     string
-	Labels     map[string][]string
-	Formatters map[string]CalendarFormatter
+	System string
+	Labels map[string][]string
+	// The raw patterns, the key is the style name, ie: date_short, or
+	// the glue pattern between a date and a time: datetime_short, datetime_short_atTime
+	Patterns map[string]string
+	// skeleton => pattern, ie: yMMMd => d MMM y
+	Skeletons map[string]string
+	// skeleton => greatest difference => pattern, ie: yMMMd => d => d–d MMM y
+	Intervals map[string]map[string]string
+	// pattern key => the day periods of the field, ie: p_format_wide_b
+	DayPeriods map[string][]*DayPeriod
+}
+
+// DayPeriod is a day period rule with the label of the locale.
+type DayPeriod struct {
+	Label  string
+	At     int
+	From   int
+	Before int
 }
 
-func AttachCalendars(locale *Locale, cldr *CLDR, ldml *Ldml) {
+// SupportedCalendars are the calendar systems loaded from the CLDR data,
+// the generic calendar provides the patterns shared by the non gregorian
+// calendars. The day periods are only loaded for the gregorian calendar.
+var SupportedCalendars = []string{"gregorian", "generic", "buddhist", "japanese", "roc", "islamic", "persian", "hebrew"}
+
+func AttachCalendars(locale *Locale, cldr *CLDR, ldml *Ldml) error {
 	for _, calendar := range ldml.Dates.Calendars.Calendar {
-		// only support gregorian calendar
-		if calendar.Type != "gregorian" {
+		if !slices.Contains(SupportedCalendars, calendar.Type) {
 			continue
 		}
 
 		locale.Calendars[calendar.Type] = &Calendar{
 			System:     calendar.Type,
 			Labels:     map[string][]string{},
-			Formatters: map[string]CalendarFormatter{},
+			Patterns:   map[string]string{},
+			Skeletons:  map[string]string{},
+			Intervals:  map[string]map[string]string{},
+			DayPeriods: map[string][]*DayPeriod{},
 		}
 	}
 
-	AttachLabels(locale, cldr, ldml)
+	return AttachLabels(locale, cldr, ldml)
 }
 
-func AttachLabels(locale *Locale, cldr *CLDR, ldml *Ldml) {
+func AttachLabels(locale *Locale, cldr *CLDR, ldml *Ldml) error {
 	for _, calendar := range ldml.Dates.Calendars.Calendar {
-
-		// only support gregorian calendar
-		if calendar.Type != "gregorian" {
+		if !slices.Contains(SupportedCalendars, calendar.Type) {
 			continue
 		}
 
+		// the runtime reads the day periods of the gregorian calendar
+		isGregorian := calendar.Type == "gregorian"
+
 		// only support abbreviated and wide, ie: Sun and Sunday
 		validTypes := []string{"abbreviated", "wide", "narrow"}
 		validFormats := []string{"format", "stand-alone"}
@@ -66,6 +83,13 @@ func AttachLabels(locale *Locale, cldr *CLDR, ldml *Ldml) {
 				key := fmt.Sprintf("m_%s_%s", month.Type, m.Type)
 				locale.Calendars[calendar.Type].Labels[key] = []string{}
 				for _, l := range m.Month {
+					// the leap variant is only used by the hebrew calendar: Adar II
+					if l.Yeartype != "" {
+						leap := key + "_" + l.Yeartype
+						locale.Calendars[calendar.Type].Labels[leap] = SetIndexedLabel(locale.Calendars[calendar.Type].Labels[leap], l.Type, 1, l.Text)
+						continue
+					}
+
 					locale.Calendars[calendar.Type].Labels[key] = append(locale.Calendars[calendar.Type].Labels[key], l.Text)
 				}
 			}
@@ -89,38 +113,128 @@ func AttachLabels(locale *Locale, cldr *CLDR, ldml *Ldml) {
 			}
 		}
 
-		for _, date := range calendar.DateFormats.DateFormatLength {
-			key := fmt.Sprintf("date_%s", date.Type)
-			Func := ParseDatePattern(date.DateFormat.Pattern)
-			Func = strings.Replace(Func, "calendarSystem", calendar.Type, -1)
+		for _, quarter := range calendar.Quarters.QuarterContext {
+			if !slices.Contains(validFormats, quarter.Type) {
+				continue
+			}
+
+			for _, q := range quarter.QuarterWidth {
+				if !slices.Contains(validTypes, q.Type) {
+					continue
+				}
+
+				labels := []string{}
+				for _, l := range q.Quarter {
+					if l.Alt != "" {
+						continue
+					}
+
+					labels = SetIndexedLabel(labels, l.Type, 1, l.Text)
+				}
+
+				locale.Calendars[calendar.Type].Labels[fmt.Sprintf("q_%s_%s", quarter.Type, q.Type)] = labels
+			}
+		}
 
-			locale.Calendars[calendar.Type].Formatters[key] = CalendarFormatter{
-				Comment: "Pattern: " + date.DateFormat.Pattern,
-				Func:    Func,
+		// -- The eras do not have a context, the label index is the era
+		// type: 0 is BC and 1 is AD for the gregorian calendar, the japanese
+		// calendar has one era per emperor. The alt
+		// variant is the secular name (BCE), it is not loaded.
+		eras := map[string][]string{}
+		for _, e := range calendar.Eras.EraNames.Era {
+			if e.Alt == "" {
+				eras["era_wide"] = SetIndexedLabel(eras["era_wide"], e.Type, 0, e.Text)
 			}
 		}
 
+		for _, e := range calendar.Eras.EraAbbr.Era {
+			if e.Alt == "" {
+				eras["era_abbreviated"] = SetIndexedLabel(eras["era_abbreviated"], e.Type, 0, e.Text)
+			}
+		}
+
+		for _, e := range calendar.Eras.EraNarrow.Era {
+			if e.Alt == "" {
+				eras["era_narrow"] = SetIndexedLabel(eras["era_narrow"], e.Type, 0, e.Text)
+			}
+		}
+
+		for key, labels := range eras {
+			locale.Calendars[calendar.Type].Labels[key] = labels
+		}
+
+		for _, date := range calendar.DateFormats.DateFormatLength {
+			key := fmt.Sprintf("date_%s", date.Type)
+			locale.Calendars[calendar.Type].Patterns[key] = date.DateFormat.Pattern
+		}
+
 		for _, time := range calendar.TimeFormats.TimeFormatLength {
 			key := fmt.Sprintf("time_%s", time.Type)
-			Func := ParseDatePattern(time.TimeFormat.Pattern)
-			Func = strings.Replace(Func, "calendarSystem", calendar.Type, -1)
+			locale.Calendars[calendar.Type].Patterns[key] = time.TimeFormat.Pattern
+		}
+
+		// -- The glue patterns to combine a date ({1}) and a time ({0}), the
+		// atTime variant is used when the date is a relative day or a weekday:
+		// {1} 'at' {0} => Yesterday at 18:04
+		for _, datetime := range calendar.DateTimeFormats.DateTimeFormatLength {
+			for _, f := range datetime.DateTimeFormat {
+				if f.Pattern == "" {
+					continue
+				}
 
-			locale.Calendars[calendar.Type].Formatters[key] = CalendarFormatter{
-				Comment: "Pattern: " + time.TimeFormat.Pattern,
-				Func:    Func,
+				key := fmt.Sprintf("datetime_%s", datetime.Type)
+				if f.Type != "" && f.Type != "standard" {
+					key = fmt.Sprintf("%s_%s", key, f.Type)
+				}
+
+				locale.Calendars[calendar.Type].Patterns[key] = f.Pattern
 			}
 		}
 
-		// -- Load the period labels for the current locale, and then use them
-		// to generate the function to get the period name based on the time
+		// -- The skeletons are used to find the best pattern for a set of
+		// fields, the count variants are not supported, only "other" is kept.
+		for _, item := range calendar.DateTimeFormats.AvailableFormats.DateFormatItem {
+			if item.Count != "" && item.Count != "other" {
+				continue
+			}
+
+			locale.Calendars[calendar.Type].Skeletons[item.ID] = item.Text
+		}
+
+		// -- The intervals patterns, the first part of the pattern (up to the
+		// first repeated field) is formatted with the start date, the second
+		// part with the end date: d–d MMM y => 10–20 janv. 2024
+		if calendar.DateTimeFormats.IntervalFormats.IntervalFormatFallback != "" {
+			locale.Calendars[calendar.Type].Patterns["interval_fallback"] = calendar.DateTimeFormats.IntervalFormats.IntervalFormatFallback
+		}
+
+		for _, item := range calendar.DateTimeFormats.IntervalFormats.IntervalFormatItem {
+			intervals := map[string]string{}
+			for _, d := range item.GreatestDifference {
+				intervals[d.ID] = d.Text
+			}
+
+			locale.Calendars[calendar.Type].Intervals[item.ID] = intervals
+		}
+
+		// -- Load the period labels for the current locale with the rules of
+		// its language, the runtime picks the period matching the time.
 		// There are multiple period group: narrow, wide, etc ...
-		if periods, ok := cldr.DayPeriods[locale.Code]; ok {
+		var periods []*DayPeriodRule
+		for _, code := range locale.GetLanguageCodes() {
+			if periods = cldr.DayPeriods[code]; periods != nil {
+				break
+			}
+		}
+
+		if periods != nil && isGregorian {
 			for _, period := range calendar.DayPeriods.DayPeriodContext {
 				if !slices.Contains(validFormats, period.Type) {
 					continue
 				}
 
-				// iterate over defined set, and then the aliases
+				// the aliases are resolved when the document is loaded, ie:
+				// the narrow periods are the abbreviated ones
 				for _, p := range period.DayPeriodWidth {
 					baseKey := fmt.Sprintf("p_%s_%s", period.Type, p.Type)
 
@@ -130,19 +244,6 @@ func AttachLabels(locale *Locale, cldr *CLDR, ldml *Ldml) {
 						"B": {"morning1", "morning2", "afternoon1", "afternoon2", "evening1", "evening2", "night1", "night2"},
 					} {
 						key := fmt.Sprintf("%s_%s", baseKey, name)
-						// Yes, this part should resolve the alias to avoid
-						// nested call, but it's not implemented yet
-						if p.Alias.Path != "" {
-							Func := GenAliasDatePeriodFunc(GetKeyAlias(p.Alias.Path, period.Type, p.Type, name))
-							Func = strings.Replace(Func, "calendarSystem", calendar.Type, -1)
-
-							locale.Calendars[calendar.Type].Formatters[key] = CalendarFormatter{
-								Comment: "This is an alias to another configuration",
-								Func:    Func,
-							}
-
-							continue
-						}
 
 						labels := map[string]string{}
 
@@ -150,286 +251,30 @@ func AttachLabels(locale *Locale, cldr *CLDR, ldml *Ldml) {
 							labels[l.Type] = l.Text
 						}
 
-						Func := GenDatePeriodFunc(locale, periods, labels, includes)
-						Func = strings.Replace(Func, "calendarSystem", calendar.Type, -1)
-						Func = strings.Replace(Func, "formatSystem", key, -1)
-
-						locale.Calendars[calendar.Type].Formatters[key] = CalendarFormatter{
-							Comment: "No pattern defined, read the periods configuration",
-							Func:    Func,
-						}
+						locale.Calendars[calendar.Type].DayPeriods[key] = GetDayPeriods(periods, labels, includes)
 					}
 				}
 			}
 		}
 	}
-}
-
-func SplitDatePattern(pattern string) (string, []string) {
-	subset := []rune("")
-
-	isLiteral := false
-	var str = ""
-	var params = []string{}
-	for _, c := range pattern {
-		// start a group literal, 39 is a quote
-		if c == 39 && !isLiteral {
-			isLiteral = true
-			continue
-		}
-
-		// close a group literal
-		if c == 39 && isLiteral {
-			isLiteral = false
-			continue
-		}
-
-		if isLiteral {
-			str += string(c)
-			continue
-		}
-
-		if c > 64 && c < 91 || c > 96 && c < 123 {
-			subset = append(subset, c)
-		} else { // new group
-			s, p := GetPattern(string(subset))
-
-			str += s
-			if len(p) > 0 {
-				params = append(params, p)
-			}
-
-			str += string(c)
-
-			subset = []rune("")
-		}
-	}
 
-	if len(subset) > 0 {
-		s, p := GetPattern(string(subset))
-
-		str += s
-		if len(p) > 0 {
-			params = append(params, p)
-		}
-	}
-
-	return str, params
+	return nil
 }
 
-func ParseDatePattern(pattern string) string {
-	str, params := SplitDatePattern(pattern)
+// GetDayPeriods returns the rules of the included periods with a label, in
+// the order of the rules.
+func GetDayPeriods(periods []*DayPeriodRule, labels map[string]string, includes []string) []*DayPeriod {
+	dayPeriods := []*DayPeriod{}
 
-	Func := `
-	if tz, err := time.LoadLocation(timeZone); err != nil {
-		panic(err)
-	} else {
-		t := tm.In(tz)
-		`
-
-	Func += "return fmt.Sprintf(\"" + str + "\", " + strings.Join(params, ", ") + ")"
-	Func += "\n}"
-
-	return Func
-}
-
-func GetPattern(pattern string) (string, string) {
-
-	// http://www.unicode.org/reports/tr35/tr35-dates.html#Date_Field_Symbol_Table
-	// http://www.unicode.org/reports/tr35/tr35-dates.html#Date_Format_Patterns
-
-	switch pattern {
-	case "yyyy":
-		return "%s", "string(t.Year())"
-	case "YYYY":
-		return "%s", "string(t.Year())"
-	case "yyy":
-		return "%03d", "t.Year()"
-	case "YYY":
-		return "%03d", "t.Year()"
-	case "yy":
-		return "%02d", "t.Year()"
-	case "YY":
-		return "%02d", "t.Year()"
-	case "y":
-		return "%d", "t.Year()"
-	case "Y":
-		return "%d", "t.Year()"
-	case "MMMM":
-		return "%s", "l.GetCalendarLabels(\"calendarSystem\", \"m_format_wide\")[t.Month()-1]"
-	case "MMM":
-		return "%s", "l.GetCalendarLabels(\"calendarSystem\", \"m_format_abbreviated\")[t.Month()-1]"
-	case "MM":
-		return "%02d", "t.Month()"
-	case "M":
-		return "%d", "t.Month()"
-	case "d":
-		return "%d", "t.Day()"
-	case "dd":
-		return "%02d", "t.Day()"
-	case "D":
-		return "%d", "t.YearDay()"
-	case "DD":
-		return "%02d", "t.YearDay()"
-	case "DDD":
-		return "%03d", "t.YearDay()"
-	case "EEEE":
-		fallthrough
-	case "eeee":
-		return "%s", "l.GetCalendarLabels(\"calendarSystem\", \"d_format_wide\")[t.Weekday()]"
-	case "E":
-		fallthrough
-	case "EE":
-		fallthrough
-	case "EEE":
-		fallthrough
-	case "eee":
-		return "%s", "l.GetCalendarLabels(\"calendarSystem\", \"d_format_abbreviated\")[t.Weekday()]"
-	case "e":
-		return "%d", "t.Weekday()"
-	case "ee":
-		return "%02d", "t.Weekday()"
-	case "h":
-		return "%d", "t.Hour()%12"
-	case "hh":
-		return "%02d", "t.Hour()%12"
-	case "H":
-		return "%d", "t.Hour()"
-	case "HH":
-		return "%02d", "t.Hour()"
-	case "m":
-		return "%d", "t.Minute()"
-	case "mm":
-		return "%02d", "t.Minute()"
-	case "s":
-		return "%d", "t.Second()"
-	case "ss":
-		return "%02d", "t.Second()"
-	case "z":
-		fallthrough
-	case "zz":
-		fallthrough
-	case "zzz":
-		fallthrough
-	case "Z":
-		fallthrough
-	case "ZZ":
-		fallthrough
-	case "ZZZ":
-		return "%s", "t.Format(\"-07:00\")"
-	case "ZZZZ":
-		return "%s", "t.Format(\"PST-07:00\")"
-	case "zzzz":
-		fallthrough
-	case "ZZZZZ":
-		return "%s", "t.Format(\"Z07:00:00\")"
-	case "a":
-		fallthrough
-	case "aa":
-		fallthrough
-	case "aaa":
-		return "%s", "l.GetCalendarFormatter(\"calendarSystem\", \"p_format_abbreviated_a\")(tm, timeZone)"
-	case "aaaa":
-		return "%s", "l.GetCalendarFormatter(\"calendarSystem\", \"p_format_wide_a\")(tm, timeZone)"
-	case "aaaaa":
-		return "%s", "l.GetCalendarFormatter(\"calendarSystem\", \"p_format_narrow_a\")(tm, timeZone)"
-	case "b":
-		fallthrough
-	case "bb":
-		fallthrough
-	case "bbb":
-		return "%s", "l.GetCalendarFormatter(\"calendarSystem\", \"p_format_abbreviated_b\")(tm, timeZone)"
-	case "bbbb":
-		return "%s", "l.GetCalendarFormatter(\"calendarSystem\", \"p_format_wide_b\")(tm, timeZone)"
-	case "bbbbb":
-		return "%s", "l.GetCalendarFormatter(\"calendarSystem\", \"p_format_narrow_b\")(tm, timeZone)"
-	case "B":
-		fallthrough
-	case "BB":
-		fallthrough
-	case "BBB":
-		return "%s", "l.GetCalendarFormatter(\"calendarSystem\", \"p_format_abbreviated_B\")(tm, timeZone)"
-	case "BBBB":
-		return "%s", "l.GetCalendarFormatter(\"calendarSystem\", \"p_format_wide_B\")(tm, timeZone)"
-	case "BBBBB":
-		return "%s", "l.GetCalendarFormatter(\"calendarSystem\", \"p_format_narrow_B\")(tm, timeZone)"
-	}
-
-	return pattern, ""
-}
-
-func GenDatePeriodFunc(locale *Locale, periods []*DayPeriodRule, labels map[string]string, includes []string) string {
-	Func := ""
-	once := false
-	// first generate the equal rule
 	for _, v := range periods {
 		if !slices.Contains(includes, v.Type) {
 			continue
 		}
 
 		if label, ok := labels[v.Type]; ok {
-			once = true
-			if v.At != -1 {
-				Func += `if hour == ` + strconv.Itoa(v.At) + ` {
-					return "` + label + `"
-				}
-			`
-			} else if v.Before > v.From {
-				Func += `if hour >= ` + strconv.Itoa(v.From) + ` && hour < ` + strconv.Itoa(v.Before) + ` {
-					return "` + label + `"
-				}
-			`
-			} else {
-				Func += `if (hour >= ` + strconv.Itoa(v.From) + ` && hour < 2400) || (hour >= 0 && hour < ` + strconv.Itoa(v.Before) + `) {
-					return "` + label + `"
-				}
-			`
-			}
-		}
-	}
-
-	if once {
-		Func = `
-		if tz, err := time.LoadLocation(timeZone); err != nil {
-			panic(err)
-		} else {
-			// periods exist, use them
-			t := tm.In(tz)
-			hour := t.Hour()*100 + t.Minute()
-
-		` + Func + `
-
+			dayPeriods = append(dayPeriods, &DayPeriod{Label: label, At: v.At, From: v.From, Before: v.Before})
 		}
-		`
 	}
 
-	if locale.Code == "root" {
-		Func += `return tm.Format("PM")`
-	} else {
-		Func += `return l.Parent.GetCalendarFormatter("calendarSystem", "formatSystem")(tm, timeZone)`
-	}
-
-	return Func
-}
-
-func GetKeyAlias(alias, context, ptype, name string) string {
-	aliases := ReadAlias(alias)
-
-	if len(aliases) == 0 {
-		panic("Unable to read/find alias: " + alias)
-	}
-
-	if len(aliases) == 1 {
-		return fmt.Sprintf("p_%s_%s_%s", context, aliases[0], name)
-	}
-
-	if len(aliases) == 2 {
-		return fmt.Sprintf("p_%s_%s_%s", aliases[0], aliases[1], name)
-	}
-
-	panic("Unsupported chain, please check alis: " + alias)
-}
-
-func GenAliasDatePeriodFunc(alias string) string {
-	return "return l.Calendars[\"calendarSystem\"].Formatters[\"" + alias + "\"](tm, timeZone)"
+	return dayPeriods
 }
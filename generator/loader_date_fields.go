@@ -0,0 +1,61 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+// <field type="day">
+//     <displayName>jour</displayName>
+//     <relative type="-1">hier</relative>
+//     <relative type="0">aujourd’hui</relative>
+//     <relative type="1">demain</relative>
+//     <relativeTime type="future">
+//         <relativeTimePattern count="one">dans {0} jour</relativeTimePattern>
+//         <relativeTimePattern count="other">dans {0} jours</relativeTimePattern>
+//     </relativeTime>
+//     <relativeTime type="past">
+//         <relativeTimePattern count="one">il y a {0} jour</relativeTimePattern>
+//         <relativeTimePattern count="other">il y a {0} jours</relativeTimePattern>
+//     </relativeTime>
+// </field>
+
+type Field struct {
+	Type        string
+	DisplayName string
+	Relative    map[string]string // offset => label, ie: -1 => yesterday
+	Future      map[string]string // plural count => pattern
+	Past        map[string]string // plural count => pattern
+}
+
+// The key is the field type: day, day-short, day-narrow, hour, ...
+func AttachFields(locale *Locale, cldr *CLDR, ldml *Ldml) error {
+	for _, f := range ldml.Dates.Fields.Field {
+		field := &Field{
+			Type:        f.Type,
+			DisplayName: f.DisplayName,
+			Relative:    map[string]string{},
+			Future:      map[string]string{},
+			Past:        map[string]string{},
+		}
+
+		for _, r := range f.Relative {
+			field.Relative[r.Type] = r.Text
+		}
+
+		for _, rt := range f.RelativeTime {
+			patterns := field.Future
+			if rt.Type == "past" {
+				patterns = field.Past
+			}
+
+			for _, p := range rt.RelativeTimePattern {
+				patterns[p.Count] = p.Text
+			}
+		}
+
+		locale.Fields[f.Type] = field
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Attach_Fields(t *testing.T) {
+	ldml, err := LoadLdml("fixtures/ldml_main.xml")
+
+	assert.Nil(t, err)
+
+	locale := &Locale{Fields: map[string]*Field{}}
+
+	assert.NoError(t, AttachFields(locale, GetCLDR(t), ldml))
+
+	assert.Contains(t, locale.Fields, "day")
+	assert.Contains(t, locale.Fields, "day-narrow")
+
+	day := locale.Fields["day"]
+	assert.Equal(t, "jour", day.DisplayName)
+	assert.Equal(t, "hier", day.Relative["-1"])
+	assert.Equal(t, "demain", day.Relative["1"])
+	assert.Equal(t, "dans {0} jours", day.Future["other"])
+	assert.Equal(t, "il y a {0} jour", day.Past["one"])
+}
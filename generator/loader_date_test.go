@@ -3,7 +3,7 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import (
 	"testing"
@@ -11,63 +11,31 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-// func Test_Date_Split_Pattern(t *testing.T) {
-// 	pattern := "y.MMMM / EEEE-d"
+func Test_Set_Indexed_Label(t *testing.T) {
+	labels := SetIndexedLabel(nil, "2", 1, "Q2")
+	labels = SetIndexedLabel(labels, "1", 1, "Q1")
 
-// 	results := SplitDatePattern(pattern)
-
-// 	assert.Equal(t, 9, len(results))
-// 	assert.Equal(t, "y", results[0])
-// 	assert.Equal(t, ".", results[1])
-// 	assert.Equal(t, "MMMM", results[2])
-// 	assert.Equal(t, " ", results[3])
-// 	assert.Equal(t, "/", results[4])
-// 	assert.Equal(t, " ", results[5])
-// 	assert.Equal(t, "EEEE", results[6])
-// 	assert.Equal(t, "-", results[7])
-// 	assert.Equal(t, "d", results[8])
-// }
-
-func Test_Date_Parse_Pattern(t *testing.T) {
-
-	pattern := "y.MMMM / EEEE-d"
-
-	str, _ := SplitDatePattern(pattern)
-
-	assert.Equal(t, "%d.%s / %s-%d", str)
+	assert.Equal(t, []string{"Q1", "Q2"}, labels)
+	assert.Equal(t, []string{"Q1", "Q2"}, SetIndexedLabel(labels, "foo", 1, "Q3"))
 }
 
-func Test_Date_Parse_PatternWithLiteral(t *testing.T) {
-	pattern := "d 'de' MMMM 'de' y"
-
-	str, _ := SplitDatePattern(pattern)
-
-	assert.Equal(t, "%d de %s de %d", str)
+func Test_Get_Day_Periods(t *testing.T) {
+	rules := []*DayPeriodRule{
+		{Type: "midnight", At: 0},
+		{Type: "am", At: -1, From: 0, Before: 1200},
+		{Type: "noon", At: 1200},
+		{Type: "pm", At: -1, From: 1200, Before: 2400},
+		{Type: "morning1", At: -1, From: 600, Before: 1200},
+	}
+
+	labels := map[string]string{"am": "AM", "pm": "PM", "noon": "noon", "morning1": "in the morning"}
+
+	periods := GetDayPeriods(rules, labels, []string{"am", "pm", "midnight", "noon"})
+	assert.Equal(t, []*DayPeriod{
+		{Label: "AM", At: -1, From: 0, Before: 1200},
+		{Label: "noon", At: 1200},
+		{Label: "PM", At: -1, From: 1200, Before: 2400},
+	}, periods)
+
+	assert.Empty(t, GetDayPeriods(rules, labels, []string{"night1"}))
 }
-
-// func Test_Gen_DatePeriodFunc(t *testing.T) {
-
-// 	periods := map[string]*DayPeriodRule{
-// 		"am": {
-// 			From:   0,
-// 			Before: 1200,
-// 			At:     -1,
-// 		},
-// 		"pm": {
-// 			From:   1200,
-// 			Before: 2400,
-// 			At:     -1,
-// 		},
-// 		"midnight": {
-// 			At: 0,
-// 		},
-// 		"noon": {
-// 			At: 1200,
-// 		},
-// 	}
-
-// 	periodFunc := GenDatePeriodFunc(periods)
-
-// 	fmt.Printf("%s", periodFunc)
-
-// }
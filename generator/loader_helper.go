@@ -3,14 +3,17 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import (
 	"encoding/xml"
-	"io/ioutil"
-	"log"
+	"io/fs"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 func ifEmptyString(s string, def string) string {
@@ -21,63 +24,148 @@ func ifEmptyString(s string, def string) string {
 	return s
 }
 
-func ifEmptyInt(s string, def string) int {
+func ifEmptyInt(s string, def string) (int, error) {
 	if s == "" {
 		s = def
 	}
 
-	if n, err := strconv.Atoi(s); err != nil {
-		panic(err)
-	} else {
-		return n
-	}
+	return strconv.Atoi(s)
 }
 
-func LoadXml(filename string, strct interface{}) error {
-	file, err := os.Open(filename)
-	if err != nil {
-		return err
+// SetIndexedLabel stores the label at the position given by the CLDR type,
+// ie: the quarter "1" is at index 0 with an offset of 1. The slice grows to
+// fit the index, the missing labels are empty.
+func SetIndexedLabel(labels []string, index string, offset int, label string) []string {
+	i, err := strconv.Atoi(index)
+	if err != nil || i-offset < 0 {
+		return labels
 	}
-	defer file.Close()
 
-	data, err := ioutil.ReadAll(file)
-	if err != nil {
-		return err
+	for len(labels) <= i-offset {
+		labels = append(labels, "")
 	}
 
-	if err = xml.Unmarshal(data, strct); err != nil {
+	labels[i-offset] = label
+
+	return labels
+}
+
+// LoadXml decodes the file of the CLDR files, the files can be read from a
+// folder or from a zip archive, see OpenCLDRFS.
+func LoadXml(fsys fs.FS, name string, strct interface{}) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return xml.Unmarshal(data, strct)
 }
 
 func LoadLdml(filename string) (*Ldml, error) {
-	file, err := os.Open(filename)
+	var ldml Ldml
+	if err := LoadXml(os.DirFS(filepath.Dir(filename)), filepath.Base(filename), &ldml); err != nil {
+		return nil, err
+	}
+
+	return &ldml, nil
+}
+
+// LoadLocaleFromFile loads the locale from the file of the CLDR files, ie:
+// main/fr.xml
+func LoadLocaleFromFile(name string, cldr *CLDR) (*Locale, error) {
+	doc := &XmlNode{}
+	if err := LoadXml(cldr.FS, name, doc); err != nil {
+		return nil, err
+	}
+
+	doc.RemoveDrafts(cldr)
+
+	parents := cldr.GetParentDocuments(strings.TrimSuffix(path.Base(name), ".xml"))
+
+	resolved, err := ResolveAliases(doc, parents, GetAliasFilter(doc, parents))
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	data, err := ioutil.ReadAll(file)
+	data, err := xml.Marshal(resolved)
 	if err != nil {
 		return nil, err
 	}
 
-	var ldml Ldml
-	err = xml.Unmarshal(data, &ldml)
+	ldml := &Ldml{}
+	if err := xml.Unmarshal(data, ldml); err != nil {
+		return nil, err
+	}
+
+	locale, err := LoadLocale(cldr, ldml)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ldml, nil
+	locale.Document = doc
+
+	return locale, nil
 }
 
-func LoadLocaleFromFile(path string, cldr *CLDR) *Locale {
-	ldml := &Ldml{}
-	if err := LoadXml(path, ldml); err != nil {
-		log.Panic(err.Error())
+// GetParentDocuments returns the documents of the loaded parents of the
+// locale, from the closest one to root.
+func (cldr *CLDR) GetParentDocuments(code string) []*XmlNode {
+	docs := []*XmlNode{}
+
+	for code != "root" {
+		code = cldr.GetParentCode(code)
+
+		parent := cldr.Locales[code]
+		if code == "root" {
+			parent = cldr.RootLocale
+		}
+
+		if parent != nil && parent.Document != nil {
+			docs = append(docs, parent.Document)
+		}
 	}
 
-	return LoadLocale(cldr, ldml)
+	return docs
+}
+
+// GetAliasFilter returns the filter of the aliases to materialize in the
+// document:
+//   - the numbering system formats are kept for the numbering systems used
+//     by the locale, root defines an alias to latn for each system.
+//   - the aliases to another calendar are skipped, the runtime already falls
+//     back to the generic and gregorian calendars.
+func GetAliasFilter(doc *XmlNode, parents []*XmlNode) func(alias *Alias) bool {
+	systems := []string{DefaultNumberSystem}
+
+	for _, d := range append([]*XmlNode{doc}, parents...) {
+		numbers := d.Find(XPath{{Name: "numbers", Attrs: map[string]string{}}})
+		if numbers == nil {
+			continue
+		}
+
+		for _, n := range numbers.Nodes {
+			switch n.XMLName.Local {
+			case "defaultNumberingSystem":
+				systems = append(systems, strings.TrimSpace(n.Text))
+			case "otherNumberingSystems":
+				for _, o := range n.Nodes {
+					systems = append(systems, strings.TrimSpace(o.Text))
+				}
+			}
+		}
+	}
+
+	return func(alias *Alias) bool {
+		for i, s := range alias.Source {
+			if system, ok := s.Attrs["numberSystem"]; ok && !slices.Contains(systems, system) {
+				return false
+			}
+
+			if s.Name == "calendar" && i < len(alias.Target) && alias.Target[i].Name == "calendar" && alias.Target[i].Attrs["type"] != s.Attrs["type"] {
+				return false
+			}
+		}
+
+		return true
+	}
 }
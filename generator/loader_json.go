@@ -0,0 +1,1170 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"testing/fstest"
+)
+
+// The cldr-json packages (cldr-core, cldr-numbers-full, cldr-dates-full,
+// cldr-localenames-full, cldr-annotations-full, ...) are converted in memory
+// to the files of a LDML common folder, the locales are then loaded like the
+// XML release. The json data has no alias elements, the values of the
+// aliases are already resolved in each locale: the stand-alone months, the
+// short fields or the formats of each numbering system. The values have no
+// draft status as only the contributed and approved ones are exported, and
+// there are no validity files: the regular codes are derived from the
+// supplemental data.
+
+// JsonNode is a value of a cldr-json document, the members of an object
+// keep the order of the document as some values are read by position.
+type JsonNode struct {
+	Key   string
+	Value string
+	Nodes []*JsonNode
+}
+
+// Get returns the node at the keys path, or nil.
+func (n *JsonNode) Get(keys ...string) *JsonNode {
+	node := n
+
+	for _, key := range keys {
+		if node == nil {
+			return nil
+		}
+
+		var found *JsonNode
+		for _, c := range node.Nodes {
+			if c.Key == key {
+				found = c
+				break
+			}
+		}
+
+		node = found
+	}
+
+	return node
+}
+
+// Attr returns the value of an attribute member, ie: "_digits".
+func (n *JsonNode) Attr(name string) string {
+	if a := n.Get("_" + name); a != nil {
+		return a.Value
+	}
+
+	return ""
+}
+
+// IsLeaf returns true if the node is a string, a number or a boolean.
+func (n *JsonNode) IsLeaf() bool {
+	return n.Nodes == nil
+}
+
+// LoadJson decodes a file of the cldr-json packages, ie:
+// cldr-core/supplemental/plurals.json
+func LoadJson(fsys fs.FS, filename string) (*JsonNode, error) {
+	file, err := fsys.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+
+	node, err := decodeJsonNode(decoder, "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", filename, err)
+	}
+
+	return node, nil
+}
+
+func decodeJsonNode(decoder *json.Decoder, key string) (*JsonNode, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &JsonNode{Key: key}
+
+	switch t := token.(type) {
+	case json.Delim:
+		node.Nodes = []*JsonNode{}
+
+		for decoder.More() {
+			name := ""
+			if t == '{' {
+				token, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				name = token.(string)
+			}
+
+			child, err := decodeJsonNode(decoder, name)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Nodes = append(node.Nodes, child)
+		}
+
+		// the closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		node.Value = t
+	case json.Number:
+		node.Value = t.String()
+	case bool:
+		node.Value = strconv.FormatBool(t)
+	}
+
+	return node, nil
+}
+
+// jsonKeyAttrs are the attributes encoded in the cldr-json keys.
+var jsonKeyAttrs = []string{"type", "count", "alt", "yeartype", "numberSystem", "status"}
+
+// ParseJsonKey splits a cldr-json key into the element name and its
+// attributes: "1000-count-one-alt-alphaNextToNumber" is the name 1000 with
+// the count one and the alt alphaNextToNumber, "relative-type--1" is the
+// name relative with the type -1.
+func ParseJsonKey(key string) (string, map[string]string) {
+	attrs := map[string]string{}
+
+	i, attr := indexJsonAttr(key, 0)
+	if i == -1 {
+		return key, attrs
+	}
+
+	name := key[:i]
+	for i != -1 {
+		start := i + len(attr) + 2
+
+		// the value has at least one character: "type--1"
+		next, nextAttr := indexJsonAttr(key, start+1)
+		if next == -1 {
+			attrs[attr] = key[start:]
+			break
+		}
+
+		attrs[attr] = key[start:next]
+		i, attr = next, nextAttr
+	}
+
+	return name, attrs
+}
+
+func indexJsonAttr(key string, from int) (int, string) {
+	for i := from; i < len(key); i++ {
+		if key[i] != '-' {
+			continue
+		}
+
+		for _, attr := range jsonKeyAttrs {
+			if strings.HasPrefix(key[i+1:], attr+"-") {
+				return i, attr
+			}
+		}
+	}
+
+	return -1, ""
+}
+
+// GetJsonLocaleCode returns the LDML code of a cldr-json locale: fr-CA is
+// fr_CA and und is root.
+func GetJsonLocaleCode(code string) string {
+	if code == "und" {
+		return "root"
+	}
+
+	return strings.ReplaceAll(code, "-", "_")
+}
+
+func newXmlNode(name string, attrs map[string]string, text string) *XmlNode {
+	node := &XmlNode{XMLName: xml.Name{Local: name}, Text: text}
+
+	names := []string{}
+	for attr, value := range attrs {
+		if value != "" {
+			names = append(names, attr)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, attr := range names {
+		node.Attrs = append(node.Attrs, xml.Attr{Name: xml.Name{Local: attr}, Value: attrs[attr]})
+	}
+
+	return node
+}
+
+// add appends a new child to the node.
+func (n *XmlNode) add(name string, attrs map[string]string, text string) *XmlNode {
+	node := newXmlNode(name, attrs, text)
+	n.Nodes = append(n.Nodes, node)
+
+	return node
+}
+
+func withAttr(attrs map[string]string, name, value string) map[string]string {
+	attrs[name] = value
+
+	return attrs
+}
+
+// LoadCLDRJson loads the CLDR from the folder of the cldr-json packages,
+// the packages are converted in memory, see ConvertJson.
+func LoadCLDRJson(jsonPath string) (*CLDR, error) {
+	fsys, err := ConvertJson(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cldr, err := LoadCLDRFS(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	cldr.Path = jsonPath
+
+	return cldr, nil
+}
+
+// ConvertJson converts the cldr-json packages of the folder into the files
+// of a LDML common folder: main, annotations, supplemental, validity and
+// properties.
+func ConvertJson(jsonPath string) (fs.FS, error) {
+	files, err := ConvertJsonFS(os.DirFS(jsonPath))
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert %s: %w", jsonPath, err)
+	}
+
+	return files, nil
+}
+
+// ConvertJsonFS converts the cldr-json packages of the files, see
+// ConvertJson.
+func ConvertJsonFS(fsys fs.FS) (fs.FS, error) {
+	files, err := fs.Glob(fsys, "*/main/*/*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	locales := map[string][]*JsonNode{}
+	for _, file := range files {
+		doc, err := LoadJson(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		code := GetJsonLocaleCode(path.Base(path.Dir(file)))
+		locales[code] = append(locales[code], doc)
+	}
+
+	if len(locales) == 0 {
+		return nil, errors.New("no cldr-json locale found in */main")
+	}
+
+	common := fstest.MapFS{}
+	for code, docs := range locales {
+		if err := addXmlFile(common, "main/"+code+".xml", ConvertJsonLocale(code, docs)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := convertJsonAnnotations(fsys, common); err != nil {
+		return nil, err
+	}
+
+	if err := convertJsonSupplemental(fsys, common); err != nil {
+		return nil, err
+	}
+
+	if err := convertJsonCoverageLevels(fsys, common); err != nil {
+		return nil, err
+	}
+
+	return common, nil
+}
+
+func addXmlFile(common fstest.MapFS, name string, node *XmlNode) error {
+	data, err := xml.MarshalIndent(node, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	common[name] = &fstest.MapFile{Data: append([]byte(xml.Header), data...)}
+
+	return nil
+}
+
+// ConvertJsonLocale builds the LDML document of a locale from its cldr-json
+// documents, only the sections read by the loaders are converted.
+func ConvertJsonLocale(code string, docs []*JsonNode) *XmlNode {
+	ldml := newXmlNode("ldml", nil, "")
+
+	identity := ldml.add("identity", nil, "")
+	identity.add("version", map[string]string{"number": "$Revision$"}, "")
+
+	for _, doc := range docs {
+		main := doc.Get("main")
+		if main == nil || len(main.Nodes) == 0 {
+			continue
+		}
+
+		for _, section := range main.Nodes[0].Nodes {
+			switch section.Key {
+			case "identity":
+				if len(identity.Nodes) > 1 {
+					continue
+				}
+
+				for _, name := range []string{"language", "script", "territory", "variant"} {
+					if v := section.Get(name); v != nil {
+						identity.add(name, map[string]string{"type": v.Value}, "")
+					}
+				}
+
+				if code == "root" {
+					identity.Nodes = identity.Nodes[:1]
+					identity.add("language", map[string]string{"type": "root"}, "")
+				}
+			case "localeDisplayNames":
+				convertJsonDisplayNames(ldml.Ensure(XPath{{Name: "localeDisplayNames"}}), section)
+			case "numbers":
+				convertJsonNumbers(ldml.Ensure(XPath{{Name: "numbers"}}), section)
+			case "dates":
+				convertJsonDates(ldml.Ensure(XPath{{Name: "dates"}}), section)
+			}
+		}
+	}
+
+	return ldml
+}
+
+var jsonDisplayNameItems = map[string]string{
+	"languages":   "language",
+	"scripts":     "script",
+	"territories": "territory",
+	"variants":    "variant",
+	"keys":        "key",
+}
+
+func convertJsonDisplayNames(names *XmlNode, n *JsonNode) {
+	for _, c := range n.Nodes {
+		item, ok := jsonDisplayNameItems[c.Key]
+		if !ok {
+			continue
+		}
+
+		e := names.Ensure(XPath{{Name: c.Key}})
+		for _, v := range c.Nodes {
+			name, attrs := ParseJsonKey(v.Key)
+			if item == "language" {
+				name = strings.ReplaceAll(name, "-", "_")
+			}
+
+			e.add(item, withAttr(attrs, "type", name), v.Value)
+		}
+	}
+}
+
+func convertJsonNumbers(numbers *XmlNode, n *JsonNode) {
+	for _, c := range n.Nodes {
+		name, attrs := ParseJsonKey(c.Key)
+
+		switch name {
+		case "defaultNumberingSystem", "minimumGroupingDigits":
+			numbers.add(name, attrs, c.Value)
+		case "otherNumberingSystems":
+			e := numbers.add(name, attrs, "")
+			for _, v := range c.Nodes {
+				e.add(v.Key, nil, v.Value)
+			}
+		case "symbols":
+			e := numbers.add(name, attrs, "")
+			for _, v := range c.Nodes {
+				if v.IsLeaf() {
+					symbol, symbolAttrs := ParseJsonKey(v.Key)
+					e.add(symbol, symbolAttrs, v.Value)
+				}
+			}
+		case "decimalFormats", "percentFormats":
+			convertJsonNumberFormats(numbers.add(name, attrs, ""), strings.TrimSuffix(name, "s"), c)
+		case "currencyFormats":
+			convertJsonCurrencyFormats(numbers.add(name, attrs, ""), c)
+		case "currencies":
+			e := numbers.Ensure(XPath{{Name: name}})
+			for _, v := range c.Nodes {
+				currency := e.add("currency", map[string]string{"type": v.Key}, "")
+				for _, f := range v.Nodes {
+					field, fieldAttrs := ParseJsonKey(f.Key)
+					currency.add(field, fieldAttrs, f.Value)
+				}
+			}
+		}
+	}
+}
+
+// convertJsonNumberFormats converts the decimal or percent formats, the
+// "standard" pattern is the one of the length without type:
+//
+//	"long": {"decimalFormat": {"1000-count-one": "0 millier"}}
+//	<decimalFormatLength type="long"><decimalFormat><pattern type="1000" count="one">
+func convertJsonNumberFormats(formats *XmlNode, kind string, n *JsonNode) {
+	for _, l := range n.Nodes {
+		name, attrs := ParseJsonKey(l.Key)
+
+		if l.IsLeaf() {
+			if name == "standard" {
+				formats.Ensure(XPath{{Name: kind + "Length"}, {Name: kind}}).add("pattern", attrs, l.Value)
+			}
+
+			continue
+		}
+
+		format := formats.add(kind+"Length", map[string]string{"type": name}, "").add(kind, nil, "")
+		if patterns := l.Get(kind); patterns != nil {
+			for _, p := range patterns.Nodes {
+				pattern, patternAttrs := ParseJsonKey(p.Key)
+				format.add("pattern", withAttr(patternAttrs, "type", pattern), p.Value)
+			}
+		}
+	}
+}
+
+// convertJsonCurrencyFormats converts the currency formats, the alt of the
+// patterns of the length without type is a suffix of the format type:
+// "standard-alphaNextToNumber".
+func convertJsonCurrencyFormats(formats *XmlNode, n *JsonNode) {
+	for _, l := range n.Nodes {
+		name, attrs := ParseJsonKey(l.Key)
+
+		switch {
+		case name == "currencySpacing":
+			convertJsonTree(formats, l)
+		case name == "unitPattern":
+			formats.add(name, attrs, l.Value)
+		case l.IsLeaf():
+			format, alt, _ := strings.Cut(name, "-")
+			if alt != "" {
+				attrs["alt"] = alt
+			}
+
+			formats.Ensure(XPath{
+				{Name: "currencyFormatLength"},
+				{Name: "currencyFormat", Attrs: map[string]string{"type": format}},
+			}).add("pattern", attrs, l.Value)
+		default:
+			length := formats.add("currencyFormatLength", map[string]string{"type": name}, "")
+			for _, f := range l.Nodes {
+				format := length.add("currencyFormat", map[string]string{"type": f.Key}, "")
+				for _, p := range f.Nodes {
+					pattern, patternAttrs := ParseJsonKey(p.Key)
+					format.add("pattern", withAttr(patternAttrs, "type", pattern), p.Value)
+				}
+			}
+		}
+	}
+}
+
+// convertJsonTree converts a node without attributes in the keys.
+func convertJsonTree(parent *XmlNode, n *JsonNode) {
+	e := parent.add(n.Key, nil, n.Value)
+	for _, c := range n.Nodes {
+		convertJsonTree(e, c)
+	}
+}
+
+func convertJsonDates(dates *XmlNode, n *JsonNode) {
+	for _, c := range n.Nodes {
+		switch c.Key {
+		case "calendars":
+			calendars := dates.Ensure(XPath{{Name: c.Key}})
+			for _, v := range c.Nodes {
+				convertJsonCalendar(calendars.add("calendar", map[string]string{"type": v.Key}, ""), v)
+			}
+		case "fields":
+			fields := dates.Ensure(XPath{{Name: c.Key}})
+			for _, v := range c.Nodes {
+				convertJsonField(fields.add("field", map[string]string{"type": v.Key}, ""), v)
+			}
+		case "timeZoneNames":
+			convertJsonTimeZoneNames(dates.Ensure(XPath{{Name: c.Key}}), c)
+		}
+	}
+}
+
+var jsonCalendarItems = map[string]string{
+	"months":     "month",
+	"days":       "day",
+	"quarters":   "quarter",
+	"dayPeriods": "dayPeriod",
+}
+
+// jsonCalendarFormats are the date and time formats: the json section, the
+// LDML formats element, the LDML element of the value.
+var jsonCalendarFormats = map[string][3]string{
+	"dateFormats":   {"dateFormats", "dateFormat", "pattern"},
+	"timeFormats":   {"timeFormats", "timeFormat", "pattern"},
+	"dateSkeletons": {"dateFormats", "dateFormat", "datetimeSkeleton"},
+	"timeSkeletons": {"timeFormats", "timeFormat", "datetimeSkeleton"},
+}
+
+func convertJsonCalendar(calendar *XmlNode, n *JsonNode) {
+	for _, c := range n.Nodes {
+		if item, ok := jsonCalendarItems[c.Key]; ok {
+			e := calendar.add(c.Key, nil, "")
+			for _, context := range c.Nodes {
+				ctx := e.add(item+"Context", map[string]string{"type": context.Key}, "")
+				for _, width := range context.Nodes {
+					w := ctx.add(item+"Width", map[string]string{"type": width.Key}, "")
+					for _, v := range width.Nodes {
+						name, attrs := ParseJsonKey(v.Key)
+						w.add(item, withAttr(attrs, "type", name), v.Value)
+					}
+				}
+			}
+
+			continue
+		}
+
+		if f, ok := jsonCalendarFormats[c.Key]; ok {
+			formats := calendar.Ensure(XPath{{Name: f[0]}})
+			for _, v := range c.Nodes {
+				name, attrs := ParseJsonKey(v.Key)
+				formats.Ensure(XPath{
+					{Name: f[1] + "Length", Attrs: map[string]string{"type": name}},
+					{Name: f[1]},
+				}).add(f[2], attrs, v.Value)
+			}
+
+			continue
+		}
+
+		switch c.Key {
+		case "eras":
+			e := calendar.add(c.Key, nil, "")
+			for _, width := range c.Nodes {
+				w := e.add(width.Key, nil, "")
+				for _, v := range width.Nodes {
+					name, attrs := ParseJsonKey(v.Key)
+					w.add("era", withAttr(attrs, "type", name), v.Value)
+				}
+			}
+		case "dateTimeFormats":
+			convertJsonDateTimeFormats(calendar.Ensure(XPath{{Name: c.Key}}), c)
+		case "dateTimeFormats-atTime":
+			formats := calendar.Ensure(XPath{{Name: "dateTimeFormats"}})
+			if standard := c.Get("standard"); standard != nil {
+				for _, v := range standard.Nodes {
+					addJsonDateTimePattern(formats, v, "atTime")
+				}
+			}
+		}
+	}
+}
+
+func convertJsonDateTimeFormats(formats *XmlNode, n *JsonNode) {
+	for _, c := range n.Nodes {
+		switch c.Key {
+		case "availableFormats":
+			e := formats.add(c.Key, nil, "")
+			for _, v := range c.Nodes {
+				name, attrs := ParseJsonKey(v.Key)
+				e.add("dateFormatItem", withAttr(attrs, "id", name), v.Value)
+			}
+		case "appendItems":
+			e := formats.add(c.Key, nil, "")
+			for _, v := range c.Nodes {
+				e.add("appendItem", map[string]string{"request": v.Key}, v.Value)
+			}
+		case "intervalFormats":
+			e := formats.add(c.Key, nil, "")
+			for _, v := range c.Nodes {
+				if v.IsLeaf() {
+					e.add(v.Key, nil, v.Value)
+					continue
+				}
+
+				item := e.add("intervalFormatItem", map[string]string{"id": v.Key}, "")
+				for _, d := range v.Nodes {
+					name, attrs := ParseJsonKey(d.Key)
+					item.add("greatestDifference", withAttr(attrs, "id", name), d.Value)
+				}
+			}
+		case "atTime":
+			for _, v := range c.Nodes {
+				addJsonDateTimePattern(formats, v, "atTime")
+			}
+		default:
+			if c.IsLeaf() {
+				addJsonDateTimePattern(formats, c, "")
+			}
+		}
+	}
+}
+
+// addJsonDateTimePattern adds the pattern combining a date and a time, the
+// node key is the length: "full" or "full-alt-variant".
+func addJsonDateTimePattern(formats *XmlNode, n *JsonNode, kind string) {
+	name, attrs := ParseJsonKey(n.Key)
+
+	format := XPathSegment{Name: "dateTimeFormat", Attrs: map[string]string{}}
+	if kind != "" {
+		format.Attrs["type"] = kind
+	}
+
+	formats.Ensure(XPath{
+		{Name: "dateTimeFormatLength", Attrs: map[string]string{"type": name}},
+		format,
+	}).add("pattern", attrs, n.Value)
+}
+
+func convertJsonField(field *XmlNode, n *JsonNode) {
+	for _, c := range n.Nodes {
+		name, attrs := ParseJsonKey(c.Key)
+
+		if name == "relativeTime" {
+			e := field.add(name, attrs, "")
+			for _, v := range c.Nodes {
+				_, patternAttrs := ParseJsonKey(v.Key)
+				e.add("relativeTimePattern", patternAttrs, v.Value)
+			}
+
+			continue
+		}
+
+		if c.IsLeaf() {
+			field.add(name, attrs, c.Value)
+		}
+	}
+}
+
+func convertJsonTimeZoneNames(names *XmlNode, n *JsonNode) {
+	for _, c := range n.Nodes {
+		name, attrs := ParseJsonKey(c.Key)
+
+		switch {
+		case name == "zone":
+			convertJsonZones(names, c, "")
+		case name == "metazone":
+			for _, v := range c.Nodes {
+				convertJsonZoneNames(names.add("metazone", map[string]string{"type": v.Key}, ""), v)
+			}
+		case c.IsLeaf():
+			names.add(name, attrs, c.Value)
+		}
+	}
+}
+
+// convertJsonZones converts the zones nested by path segment:
+//
+//	"America": {"Argentina": {"Rio_Gallegos": {"exemplarCity": "Río Gallegos"}}}
+//	<zone type="America/Argentina/Rio_Gallegos"><exemplarCity>Río Gallegos</exemplarCity></zone>
+func convertJsonZones(names *XmlNode, n *JsonNode, prefix string) {
+	for _, c := range n.Nodes {
+		path := c.Key
+		if prefix != "" {
+			path = prefix + "/" + c.Key
+		}
+
+		if isJsonZone(c) {
+			convertJsonZoneNames(names.add("zone", map[string]string{"type": path}, ""), c)
+		} else {
+			convertJsonZones(names, c, path)
+		}
+	}
+}
+
+func isJsonZone(n *JsonNode) bool {
+	for _, c := range n.Nodes {
+		if name, _ := ParseJsonKey(c.Key); name == "exemplarCity" || name == "long" || name == "short" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func convertJsonZoneNames(zone *XmlNode, n *JsonNode) {
+	for _, c := range n.Nodes {
+		name, attrs := ParseJsonKey(c.Key)
+
+		e := zone.add(name, attrs, c.Value)
+		for _, v := range c.Nodes {
+			e.add(v.Key, nil, v.Value)
+		}
+	}
+}
+
+// convertJsonAnnotations converts the annotations, the keywords are joined
+// with " | " as in the LDML files.
+func convertJsonAnnotations(fsys fs.FS, common fstest.MapFS) error {
+	files, err := fs.Glob(fsys, "*/annotations/*/annotations.json")
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		doc, err := LoadJson(fsys, file)
+		if err != nil {
+			return err
+		}
+
+		ldml := newXmlNode("ldml", nil, "")
+		identity := ldml.add("identity", nil, "")
+		identity.add("language", map[string]string{"type": doc.Get("annotations", "identity", "language").Value}, "")
+
+		annotations := ldml.add("annotations", nil, "")
+		if list := doc.Get("annotations", "annotations"); list != nil {
+			for _, a := range list.Nodes {
+				for _, kind := range []string{"default", "tts"} {
+					values := []string{}
+					if v := a.Get(kind); v != nil {
+						for _, value := range v.Nodes {
+							values = append(values, value.Value)
+						}
+					}
+
+					if len(values) == 0 {
+						continue
+					}
+
+					attrs := map[string]string{"cp": a.Key}
+					if kind == "tts" {
+						attrs["type"] = kind
+					}
+
+					annotations.add("annotation", attrs, strings.Join(values, " | "))
+				}
+			}
+		}
+
+		code := GetJsonLocaleCode(path.Base(path.Dir(file)))
+		if err := addXmlFile(common, "annotations/"+code+".xml", ldml); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonSupplementalFiles are the LDML files of the supplemental sections.
+var jsonSupplementalFiles = map[string]string{
+	"currencyData":           "supplementalData.xml",
+	"codeMappings":           "supplementalData.xml",
+	"territoryContainment":   "supplementalData.xml",
+	"weekData":               "supplementalData.xml",
+	"calendarPreferenceData": "supplementalData.xml",
+	"parentLocales":          "supplementalData.xml",
+	"dayPeriodRuleSet":       "dayPeriods.xml",
+	"plurals":                "plurals.xml",
+	"likelySubtags":          "likelySubtags.xml",
+	"metaZones":              "metaZones.xml",
+	"languageMatching":       "languageInfo.xml",
+}
+
+// convertJsonSupplemental converts the supplemental files of cldr-core and
+// derives the validity files.
+func convertJsonSupplemental(fsys fs.FS, common fstest.MapFS) error {
+	files, err := fs.Glob(fsys, "*/supplemental/*.json")
+	if err != nil {
+		return err
+	}
+
+	version := ""
+	docs := map[string]*XmlNode{}
+	territoryAliases := map[string]string{}
+
+	get := func(file string) *XmlNode {
+		if _, ok := docs[file]; !ok {
+			docs[file] = newXmlNode("supplementalData", nil, "")
+			docs[file].add("version", map[string]string{"number": "$Revision$"}, "")
+		}
+
+		return docs[file]
+	}
+
+	for _, file := range files {
+		doc, err := LoadJson(fsys, file)
+		if err != nil {
+			return err
+		}
+
+		supplemental := doc.Get("supplemental")
+		if supplemental == nil {
+			continue
+		}
+
+		for _, c := range supplemental.Nodes {
+			name, attrs := ParseJsonKey(c.Key)
+
+			if name == "version" {
+				version = ifEmptyString(version, c.Attr("cldrVersion"))
+				continue
+			}
+
+			if name == "metadata" {
+				if aliases := c.Get("alias", "territoryAlias"); aliases != nil {
+					for _, a := range aliases.Nodes {
+						territoryAliases[a.Key] = a.Attr("reason")
+					}
+				}
+
+				continue
+			}
+
+			target, ok := jsonSupplementalFiles[name]
+			if !ok {
+				continue
+			}
+
+			convertJsonSupplementalSection(get(target), name, attrs, c)
+		}
+	}
+
+	if len(docs) == 0 {
+		return errors.New("no cldr-json supplemental file found in */supplemental")
+	}
+
+	for _, file := range []string{"supplementalData.xml", "dayPeriods.xml", "plurals.xml", "likelySubtags.xml", "metaZones.xml", "languageInfo.xml"} {
+		doc := get(file)
+		if version != "" {
+			doc.Nodes[0].Attrs = append(doc.Nodes[0].Attrs, xml.Attr{Name: xml.Name{Local: "cldrVersion"}, Value: version})
+		}
+
+		if err := addXmlFile(common, "supplemental/"+file, doc); err != nil {
+			return err
+		}
+	}
+
+	for file, codes := range GetJsonValidities(get("supplementalData.xml"), get("likelySubtags.xml"), territoryAliases) {
+		doc := newXmlNode("supplementalData", nil, "")
+		doc.add("version", map[string]string{"number": "$Revision$", "cldrVersion": version}, "")
+		doc.add("idValidity", nil, "").add("id", map[string]string{
+			"type":     strings.TrimSuffix(file, ".xml"),
+			"idStatus": "regular",
+		}, strings.Join(codes, " "))
+
+		if err := addXmlFile(common, "validity/"+file, doc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func convertJsonSupplementalSection(doc *XmlNode, name string, attrs map[string]string, n *JsonNode) {
+	switch name {
+	case "currencyData":
+		data := doc.Ensure(XPath{{Name: name}})
+		if fractions := n.Get("fractions"); fractions != nil {
+			e := data.add("fractions", nil, "")
+			for _, f := range fractions.Nodes {
+				e.add("info", withAttr(getJsonAttrs(f), "iso4217", f.Key), "")
+			}
+		}
+
+		if regions := n.Get("region"); regions != nil {
+			for _, r := range regions.Nodes {
+				region := data.add("region", map[string]string{"iso3166": r.Key}, "")
+				for _, item := range r.Nodes {
+					for _, c := range item.Nodes {
+						region.add("currency", withAttr(getJsonAttrs(c), "iso4217", c.Key), "")
+					}
+				}
+			}
+		}
+	case "codeMappings":
+		data := doc.Ensure(XPath{{Name: name}})
+		for _, c := range n.Nodes {
+			if len(c.Key) == 3 && c.Attr("alpha3") == "" {
+				data.add("currencyCodes", withAttr(getJsonAttrs(c), "type", c.Key), "")
+			} else {
+				data.add("territoryCodes", withAttr(getJsonAttrs(c), "type", c.Key), "")
+			}
+		}
+	case "territoryContainment":
+		data := doc.Ensure(XPath{{Name: name}})
+		for _, c := range n.Nodes {
+			code, groupAttrs := ParseJsonKey(c.Key)
+			for k, v := range getJsonAttrs(c) {
+				groupAttrs[k] = v
+			}
+
+			if groupAttrs["status"] == "grouping" {
+				groupAttrs["status"], groupAttrs["grouping"] = "", "true"
+			}
+
+			contains := []string{}
+			if list := c.Get("_contains"); list != nil {
+				for _, v := range list.Nodes {
+					contains = append(contains, v.Value)
+				}
+			}
+
+			groupAttrs["contains"] = strings.Join(contains, " ")
+			data.add("group", withAttr(groupAttrs, "type", code), "")
+		}
+	case "weekData":
+		data := doc.Ensure(XPath{{Name: name}})
+		for _, c := range n.Nodes {
+			value, ok := map[string]string{"minDays": "count", "firstDay": "day", "weekendStart": "day", "weekendEnd": "day"}[c.Key]
+			if !ok {
+				continue
+			}
+
+			for _, v := range c.Nodes {
+				territory, territoryAttrs := ParseJsonKey(v.Key)
+				territoryAttrs["territories"] = territory
+				territoryAttrs[value] = v.Value
+				data.add(c.Key, territoryAttrs, "")
+			}
+		}
+	case "calendarPreferenceData":
+		data := doc.Ensure(XPath{{Name: name}})
+		for _, c := range n.Nodes {
+			data.add("calendarPreference", map[string]string{"territories": c.Key, "ordering": c.Value}, "")
+		}
+	case "parentLocales":
+		for _, c := range n.Nodes {
+			component := ""
+			if c.Key != "parentLocale" {
+				component = c.Key
+			}
+
+			parents := doc.add(name, map[string]string{"component": component}, "")
+			for _, v := range c.Nodes {
+				if v.IsLeaf() {
+					parents.add("parentLocale", map[string]string{"locales": GetJsonLocaleCode(v.Key), "parent": GetJsonLocaleCode(v.Value)}, "")
+				}
+			}
+		}
+	case "dayPeriodRuleSet":
+		set := doc.add(name, attrs, "")
+		for _, c := range n.Nodes {
+			rules := set.add("dayPeriodRules", map[string]string{"locales": GetJsonLocaleCode(c.Key)}, "")
+			for _, r := range c.Nodes {
+				rules.add("dayPeriodRule", withAttr(getJsonAttrs(r), "type", r.Key), "")
+			}
+		}
+	case "plurals":
+		plurals := doc.add(name, attrs, "")
+		for _, c := range n.Nodes {
+			rules := plurals.add("pluralRules", map[string]string{"locales": GetJsonLocaleCode(c.Key)}, "")
+			for _, r := range c.Nodes {
+				_, ruleAttrs := ParseJsonKey(r.Key)
+				rules.add("pluralRule", ruleAttrs, r.Value)
+			}
+		}
+	case "likelySubtags":
+		data := doc.Ensure(XPath{{Name: name}})
+		for _, c := range n.Nodes {
+			data.add("likelySubtag", map[string]string{
+				"from": strings.ReplaceAll(c.Key, "-", "_"),
+				"to":   strings.ReplaceAll(c.Value, "-", "_"),
+			}, "")
+		}
+	case "metaZones":
+		data := doc.Ensure(XPath{{Name: name}})
+		if info := n.Get("metazoneInfo", "timezone"); info != nil {
+			convertJsonMetaZones(data.add("metazoneInfo", nil, ""), info, "")
+		}
+
+		if zones := n.Get("metazones"); zones != nil {
+			e := data.add("mapTimezones", map[string]string{"type": "metazones"}, "")
+			for _, item := range zones.Nodes {
+				if m := item.Get("mapZone"); m != nil {
+					e.add("mapZone", getJsonAttrs(m), "")
+				}
+			}
+		}
+	case "languageMatching":
+		data := doc.Ensure(XPath{{Name: name}})
+		for _, c := range n.Nodes {
+			matches := data.add("languageMatches", map[string]string{"type": strings.ReplaceAll(c.Key, "-", "_")}, "")
+			for _, item := range c.Nodes {
+				convertJsonLanguageMatch(matches, item)
+			}
+		}
+	}
+}
+
+// getJsonAttrs returns the attribute members of the node: "_from" is from.
+func getJsonAttrs(n *JsonNode) map[string]string {
+	attrs := map[string]string{}
+	for _, c := range n.Nodes {
+		if strings.HasPrefix(c.Key, "_") && c.IsLeaf() {
+			attrs[strings.TrimPrefix(c.Key, "_")] = c.Value
+		}
+	}
+
+	return attrs
+}
+
+// convertJsonMetaZones converts the time zones nested by path segment, a
+// zone is the list of its metazones.
+func convertJsonMetaZones(info *XmlNode, n *JsonNode, prefix string) {
+	for _, c := range n.Nodes {
+		path := c.Key
+		if prefix != "" {
+			path = prefix + "/" + c.Key
+		}
+
+		if len(c.Nodes) == 0 || c.Nodes[0].Key != "" {
+			convertJsonMetaZones(info, c, path)
+			continue
+		}
+
+		zone := info.add("timezone", map[string]string{"type": path}, "")
+		for _, item := range c.Nodes {
+			if m := item.Get("usesMetazone"); m != nil {
+				zone.add("usesMetazone", getJsonAttrs(m), "")
+			}
+		}
+	}
+}
+
+// convertJsonLanguageMatch converts an item of the language matches, the
+// paradigm locales, a variable ("$enUS") or a match between two locales.
+func convertJsonLanguageMatch(matches *XmlNode, n *JsonNode) {
+	for _, c := range n.Nodes {
+		attrs := getJsonAttrs(c)
+
+		switch {
+		case c.Key == "paradigmLocales":
+			matches.add(c.Key, map[string]string{"locales": strings.ReplaceAll(attrs["locales"], "-", "_")}, "")
+		case strings.HasPrefix(c.Key, "$"):
+			matches.add("matchVariable", map[string]string{"id": c.Key, "value": attrs["value"]}, "")
+		default:
+			if attrs["desired"] == "" {
+				attrs["desired"] = c.Key
+			}
+
+			for _, name := range []string{"desired", "supported"} {
+				attrs[name] = strings.ReplaceAll(attrs[name], "-", "_")
+			}
+
+			matches.add("languageMatch", attrs, "")
+		}
+	}
+}
+
+// GetJsonValidities returns the regular codes of the validity files, the
+// cldr-json packages do not provide them:
+//   - region: the territories with codes, without the private use codes
+//     and the deprecated territories.
+//   - currency: the currencies with codes, fractions or used by a region.
+//   - language: the languages of the likely subtags.
+func GetJsonValidities(data, likelySubtags *XmlNode, territoryAliases map[string]string) map[string][]string {
+	regions, currencies, languages := map[string]bool{}, map[string]bool{}, map[string]bool{}
+
+	for _, n := range data.Nodes {
+		for _, c := range n.Nodes {
+			switch c.XMLName.Local {
+			case "territoryCodes":
+				if code := c.GetAttr("type"); !isPrivateUseRegion(code) && territoryAliases[code] != "deprecated" {
+					regions[code] = true
+				}
+			case "currencyCodes":
+				currencies[c.GetAttr("type")] = true
+			case "fractions":
+				for _, i := range c.Nodes {
+					currencies[i.GetAttr("iso4217")] = true
+				}
+			case "region":
+				for _, i := range c.Nodes {
+					currencies[i.GetAttr("iso4217")] = true
+				}
+			}
+		}
+	}
+
+	for _, n := range likelySubtags.Nodes {
+		for _, c := range n.Nodes {
+			if language, _, _ := strings.Cut(c.GetAttr("from"), "_"); language != "und" {
+				languages[language] = true
+			}
+		}
+	}
+
+	delete(currencies, "DEFAULT")
+	delete(currencies, "XXX")
+
+	list := func(codes map[string]bool) []string {
+		l := []string{}
+		for code := range codes {
+			l = append(l, code)
+		}
+
+		sort.Strings(l)
+
+		return l
+	}
+
+	return map[string][]string{
+		"region.xml":   list(regions),
+		"currency.xml": list(currencies),
+		"language.xml": list(languages),
+	}
+}
+
+// isPrivateUseRegion returns true for AA, QM~QZ, XA~XZ (XK excepted) and
+// ZZ.
+func isPrivateUseRegion(code string) bool {
+	if code == "AA" || code == "ZZ" {
+		return true
+	}
+
+	if len(code) != 2 {
+		return false
+	}
+
+	return (code[0] == 'Q' && code[1] >= 'M') || (code[0] == 'X' && code != "XK")
+}
+
+// convertJsonCoverageLevels adds the coverage levels of the locales in the
+// format of properties/coverageLevels.txt.
+func convertJsonCoverageLevels(fsys fs.FS, common fstest.MapFS) error {
+	files, err := fs.Glob(fsys, "*/coverageLevels.json")
+	if err != nil || len(files) == 0 {
+		return err
+	}
+
+	doc, err := LoadJson(fsys, files[0])
+	if err != nil {
+		return err
+	}
+
+	lines := []string{}
+	if levels := doc.Get("effectiveCoverageLevels"); levels != nil {
+		for _, l := range levels.Nodes {
+			lines = append(lines, GetJsonLocaleCode(l.Key)+" ;\t"+l.Value+" ;\t")
+		}
+	}
+
+	common["properties/coverageLevels.txt"] = &fstest.MapFile{Data: []byte(strings.Join(lines, "\n") + "\n")}
+
+	return nil
+}
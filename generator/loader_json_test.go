@@ -0,0 +1,131 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse_Json_Key(t *testing.T) {
+	for _, c := range []struct {
+		key   string
+		name  string
+		attrs map[string]string
+	}{
+		{"standard", "standard", map[string]string{}},
+		{"stand-alone", "stand-alone", map[string]string{}},
+		{"GB-alt-short", "GB", map[string]string{"alt": "short"}},
+		{"relative-type--1", "relative", map[string]string{"type": "-1"}},
+		{"1000-count-one-alt-alphaNextToNumber", "1000", map[string]string{"count": "one", "alt": "alphaNextToNumber"}},
+		{"symbols-numberSystem-latn", "symbols", map[string]string{"numberSystem": "latn"}},
+		{"7-yeartype-leap", "7", map[string]string{"yeartype": "leap"}},
+	} {
+		name, attrs := ParseJsonKey(c.key)
+
+		assert.Equal(t, c.name, name, c.key)
+		assert.Equal(t, c.attrs, attrs, c.key)
+	}
+}
+
+func Test_Convert_Json(t *testing.T) {
+	cldr, err := LoadCLDRJson("fixtures/cldr-json")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "44", cldr.Version)
+	assert.Equal(t, []string{"CA", "FR", "GB"}, cldr.GetValidity("region", "regular").List)
+	assert.Equal(t, []string{"CHF", "EUR", "FRF", "JPY"}, cldr.GetValidity("currency", "regular").List)
+	assert.Equal(t, "0", cldr.Currencies["JPY"].Digits)
+	assert.Equal(t, "392", cldr.Currencies["JPY"].Numeric)
+	assert.Equal(t, "root", cldr.GetParentCode("sr_Latn"))
+	assert.Equal(t, "fr_Latn_FR", cldr.LikelySubtags["fr"])
+	assert.Equal(t, []string{"019", "150"}, cldr.TerritoryContainment["001"][:2])
+	assert.Equal(t, 0, cldr.WeekData["CA"].FirstDay)
+	assert.Equal(t, 4, cldr.WeekData["FR"].MinDays)
+	assert.Equal(t, []string{"en", "en_GB", "es", "es_419"}, cldr.ParadigmLocales)
+	assert.Equal(t, "zh_Hant", cldr.LanguageMatches[1].Desired)
+	assert.Len(t, cldr.Plurals["fr"], 3)
+	assert.Equal(t, &MetaZone{Type: "Europe/Paris", Zone: "Europe_Central"}, cldr.MetaZones[1])
+
+	levels, err := LoadCoverageLevels(cldr.FS, "properties/coverageLevels.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"fr": "modern", "fr_CA": "modern"}, levels)
+
+	locale, err := cldr.GetLocale("fr_CA", func(locale *Locale) {})
+	assert.NoError(t, err)
+	fr := locale.Parent
+
+	assert.Equal(t, "fr", fr.Code)
+	assert.Equal(t, "CA", locale.Territory)
+	assert.Equal(t, "y-MM-dd", locale.Calendars["gregorian"].Patterns["date_short"])
+
+	assert.Equal(t, ",", fr.Number.Symbols["latn"].Decimal)
+	assert.Equal(t, "#,##0 %", fr.Number.Percents["latn"]["default"][0].Pattern)
+	assert.Equal(t, "France", fr.Territories["FR"].Name)
+	assert.Equal(t, "R.-U.", fr.Territories["GB"].Alts["short"])
+	assert.Equal(t, "yen japonais", fr.Currencies["JPY"].Name)
+	assert.Equal(t, []*Annotation{
+		{Cp: "€", Label: "devise | EUR | euro"},
+		{Cp: "€", Type: "tts", Label: "euro"},
+	}, fr.Annotations)
+
+	gregorian := fr.Calendars["gregorian"]
+	assert.Equal(t, "EEEE d MMMM y", gregorian.Patterns["date_full"])
+	assert.Equal(t, "janv.", gregorian.Labels["m_format_abbreviated"][0])
+	// the values of the aliases are in the cldr-json data
+	assert.Equal(t, "janvier", gregorian.Labels["m_stand-alone_wide"][0])
+	assert.Equal(t, "dimanche", gregorian.Labels["d_stand-alone_wide"][0])
+	assert.Equal(t, "av. J.-C.", gregorian.Labels["era_wide"][0])
+
+	assert.Equal(t, "dans {0} ans", fr.Fields["year"].Future["other"])
+	assert.Equal(t, "l’année dernière", fr.Fields["year"].Relative["-1"])
+	assert.Equal(t, "UTC{0}", fr.TimeFormat.Gmt)
+}
+
+// The cldr-json fixture contains the data of fixtures/core.zip, both
+// sources must generate the same locales.
+func Test_Convert_Json_Same_Output(t *testing.T) {
+	generate := func(load func(path string) (*CLDR, error), path string) map[string]string {
+		cldr, err := load(path)
+		assert.NoError(t, err)
+
+		defer cldr.Close()
+
+		files := map[string]string{}
+		for _, code := range []string{"root", "fr", "fr_CA"} {
+			locale, err := cldr.GetLocale(code, func(locale *Locale) {})
+			assert.NoError(t, err)
+
+			buffer := bytes.NewBuffer([]byte{})
+			assert.NoError(t, WriteLocaleGo(locale, buffer))
+
+			files[code] = buffer.String()
+		}
+
+		return files
+	}
+
+	expected, actual := generate(LoadCLDR, "fixtures/core.zip"), generate(LoadCLDRJson, "fixtures/cldr-json")
+	for _, code := range []string{"root", "fr", "fr_CA"} {
+		assert.NotEmpty(t, expected[code])
+		assert.Equal(t, expected[code], actual[code], code)
+	}
+}
+
+func Test_Convert_Json_FS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cldr-core/supplemental/plurals.json": {Data: []byte(`{"supplemental": {"version": {"_cldrVersion": "44"}}}`)},
+	}
+
+	_, err := ConvertJsonFS(fsys)
+	assert.EqualError(t, err, "no cldr-json locale found in */main")
+
+	_, err = LoadCLDRJson("fixtures/missing")
+	assert.EqualError(t, err, "unable to convert fixtures/missing: no cldr-json locale found in */main")
+}
@@ -3,10 +3,12 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"strings"
 )
 
@@ -23,7 +25,10 @@ type Locale struct {
 	Parent          *Locale
 	Code            string
 	Name            string
+	Language        string
+	Script          string
 	Territory       string
+	Variant         string
 	Territories     map[string]*Territory
 	Currencies      map[string]*Currency
 	CurrencySymbols map[string]Symbol
@@ -33,14 +38,41 @@ type Locale struct {
 	Annotations     []*Annotation
 	Calendars       map[string]*Calendar
 	TimeFormat      *TimeFormat
+	MetaZoneNames   map[string]map[string]string
+	ZoneNames       map[string]map[string]string
+	Fields          map[string]*Field
+	PluralRules     []*PluralRule
+	// the LDML document before the aliases resolution, used to resolve the
+	// aliases of the child locales
+	Document *XmlNode
+	// time zone => metazone, only defined on the root locale
+	MetaZones map[string]string
+	// time zone => CLDR time zone, only defined on the root locale
+	ZoneAliases map[string]string
+	// CLDR time zone => short BCP 47 id, only defined on the root locale
+	ZoneIds map[string]string
+	// the territory data, only defined on the root locale
+	WeekData            map[string]*WeekData
+	LikelySubtags       map[string]string
+	CalendarPreferences map[string][]string
+	LanguageMatches     []*LanguageMatch
+	MatchVariables      map[string][]string
+	ParadigmLocales     []string
+	// the data attached by the handlers of the plugins
+	Extra map[string]interface{}
 }
 
-func LoadLocale(cldr *CLDR, ldml *Ldml) *Locale {
+// LoadLocale attaches the data of the document to the locale with the
+// built-in loaders, then with the handlers of the plugins.
+func LoadLocale(cldr *CLDR, ldml *Ldml) (*Locale, error) {
 	locale := &Locale{
 		IsRoot:    ldml.Identity.Language.Type == "root",
 		Code:      ldml.Identity.Language.Type,
 		Name:      ldml.Identity.Language.Type,
+		Language:  ldml.Identity.Language.Type,
+		Script:    ldml.Identity.Script.Type,
 		Territory: ldml.Identity.Territory.Type,
+		Variant:   ldml.Identity.Variant.Type,
 		Parent:    nil,
 		Number: &Number{
 			Symbols:    map[string]*Symbol{},
@@ -52,43 +84,78 @@ func LoadLocale(cldr *CLDR, ldml *Ldml) *Locale {
 		Territories: map[string]*Territory{},
 		Currencies:  map[string]*Currency{},
 		Calendars:   map[string]*Calendar{},
+		Fields:      map[string]*Field{},
+
+		MetaZones:     map[string]string{},
+		MetaZoneNames: map[string]map[string]string{},
+		ZoneNames:     map[string]map[string]string{},
+		Extra:         map[string]interface{}{},
+	}
+
+	// the code contains the language, script, territory and variant subtags:
+	// sr_Latn, zh_Hant_HK
+	for _, subtag := range []string{locale.Script, locale.Territory, locale.Variant} {
+		if subtag != "" {
+			locale.Code = locale.Code + "_" + subtag
+		}
 	}
 
+	locale.IsBase = !locale.IsRoot && locale.Code == locale.Language
+
+	// the parent must be loaded before the locale
 	if !locale.IsRoot {
-		locale.Parent = cldr.RootLocale
+		code := cldr.GetParentCode(locale.Code)
+
+		if code == "root" {
+			locale.Parent = cldr.RootLocale
+		} else {
+			locale.Parent = cldr.Locales[code]
+		}
+
+		if locale.Parent == nil {
+			return nil, fmt.Errorf("the parent locale %s of the locale %s is not loaded", code, locale.Code)
+		}
 	}
 
-	if locale.Territory != "" {
-		locale.Parent = cldr.Locales[locale.Code]
-		locale.Code = locale.Code + "_" + locale.Territory
-	} else {
-		locale.IsBase = !locale.IsRoot
+	handlers := []LdmlHandler{
+		AttachKeys,
+		AttachAnnotations,
+		AttachCurrencies,
+		AttachTerritories,
+		AttachTimeZones,
+		AttachTimeFormat,
+		AttachZoneNames,
+		AttachNumber,
+		AttachCalendars,
+		AttachFields,
+		AttachPlurals,
+		AttachRootData,
 	}
 
-	AttachKeys(locale, cldr, ldml)
-	AttachAnnotations(locale, cldr, ldml)
-	AttachCurrencies(locale, cldr, ldml)
-	AttachTerritories(locale, cldr, ldml)
-	AttachTimeZones(locale, cldr, ldml)
-	AttachTimeFormat(locale, cldr, ldml)
-	AttachNumber(locale, cldr, ldml)
-	AttachCalendars(locale, cldr, ldml)
+	for _, handler := range append(handlers, cldr.LdmlHandlers...) {
+		if err := handler(locale, cldr, ldml); err != nil {
+			return nil, err
+		}
+	}
 
-	return locale
+	return locale, nil
 }
 
 // The keys are used to filter data in each annotation files
 // currency is device is french,
 //
 //	<annotation cp="€">devise | EUR | euro</annotation>
-func AttachKeys(locale *Locale, cldr *CLDR, ldml *Ldml) {
+func AttachKeys(locale *Locale, cldr *CLDR, ldml *Ldml) error {
 	for _, key := range ldml.LocaleDisplayNames.Keys.Key {
 		locale.Keys[key.Type] = strings.ToLower(key.Text)
 	}
+
+	return nil
 }
 
-// For now, we only attach currency key, and draft must be empty
-func AttachAnnotations(locale *Locale, cldr *CLDR, ldml *Ldml) {
+// For now, we only attach currency key, the draft status must be at or
+// above the draft level
+func AttachAnnotations(locale *Locale, cldr *CLDR, ldml *Ldml) error {
 	annotation := &XmlAnnotation{}
 
 	module := locale.Code
@@ -98,15 +165,19 @@ func AttachAnnotations(locale *Locale, cldr *CLDR, ldml *Ldml) {
 
 	// load the related annotation for the root file, however the
 	// file is empty, so we fallback to the en one.
-	err := LoadXml(cldr.Path+"/annotations/"+module+".xml", annotation)
+	err := LoadXml(cldr.FS, "annotations/"+module+".xml", annotation)
 
-	if err != nil {
+	if errors.Is(err, fs.ErrNotExist) {
 		fmt.Printf("Unable to find the annotation: %s\n", module)
-		return
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to load the annotation %s: %w", module, err)
 	}
 
 	for _, a := range annotation.Annotations.Annotation {
-		if a.Draft != "" {
+		if !cldr.IsDraftIncluded(a.Draft) {
 			continue
 		}
 
@@ -118,4 +189,48 @@ func AttachAnnotations(locale *Locale, cldr *CLDR, ldml *Ldml) {
 
 		locale.Annotations = append(locale.Annotations, annotation)
 	}
+
+	return nil
+}
+
+// GetLanguageCodes returns the codes used to find the data defined per
+// language in the supplemental files. A locale inheriting from root, like
+// sr_Latn, cannot rely on its parent and uses the rules of its language.
+func (locale *Locale) GetLanguageCodes() []string {
+	if locale.IsRoot || locale.IsBase || !locale.Parent.IsRoot {
+		return []string{locale.Code}
+	}
+
+	return []string{locale.Code, locale.Language}
+}
+
+func AttachPlurals(locale *Locale, cldr *CLDR, ldml *Ldml) error {
+	// plural rules are mostly defined per language, a locale without its
+	// own rules, like fr_CA, relies on the parent. A territory locale can
+	// define its rules: pt_PT
+	for _, code := range locale.GetLanguageCodes() {
+		if rules, ok := cldr.Plurals[code]; ok {
+			locale.PluralRules = rules
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// AttachRootData attaches the data not related to a language, the values
+// are only available on the root locale.
+func AttachRootData(locale *Locale, cldr *CLDR, ldml *Ldml) error {
+	if !locale.IsRoot {
+		return nil
+	}
+
+	locale.WeekData = cldr.WeekData
+	locale.LikelySubtags = cldr.LikelySubtags
+	locale.CalendarPreferences = cldr.CalendarPreferences
+	locale.LanguageMatches = cldr.LanguageMatches
+	locale.MatchVariables = cldr.GetMatchVariables()
+	locale.ParadigmLocales = cldr.ParadigmLocales
+
+	return nil
 }
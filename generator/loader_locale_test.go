@@ -3,21 +3,34 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
-func GetCLDR() *CLDR {
-	cldr := &CLDR{
-		Validities: []*Validity{
-			{"region", []string{"AC", "AD", "CD", "CF", "CG", "HK", "HM", "HN", "IO"}, "regular"},
-		},
+// GetCLDR loads the zipped fixture, the regions and the root territories
+// are the ones of the ldml fixtures.
+func GetCLDR(t *testing.T) *CLDR {
+	cldr, err := LoadCLDR("fixtures/core.zip")
+	assert.NoError(t, err)
+
+	t.Cleanup(func() { cldr.Close() })
+
+	cldr.Validities = []*Validity{
+		{"region", []string{"AC", "AD", "CD", "CF", "CG", "HK", "HM", "HN", "IO"}, "regular"},
 	}
 
+	for _, code := range cldr.Validities[0].List {
+		cldr.Territories[code] = &Territory{Code: code}
+	}
+
+	_, err = cldr.GetLocale("root", func(locale *Locale) {})
+	assert.NoError(t, err)
+
 	return cldr
 }
 
@@ -34,17 +47,79 @@ func Test_Load_Simple_Ldml(t *testing.T) {
 func Test_Load_Full_Ldml(t *testing.T) {
 	ldml, err := LoadLdml("fixtures/ldml_main.xml")
 
-	locale := LoadLocale(GetCLDR(), ldml)
-
 	assert.Nil(t, err)
 
+	cldr := GetCLDR(t)
+	locale, err := LoadLocale(cldr, ldml)
+	assert.NoError(t, err)
+
 	assert.Equal(t, "$Revision$", ldml.Identity.Version.Number)
 	// assert.Equal(t, "fr", ldml.Identity.Language.Type)
 	// assert.Equal(t, "YT", ldml.Identity.Territory.Type)
 	assert.NotNil(t, ldml.LocaleDisplayNames.Territories)
 	assert.Len(t, ldml.LocaleDisplayNames.Territories.Territory, 14)
 
-	AttachTerritories(locale, GetCLDR(), ldml)
+	assert.NoError(t, AttachTerritories(locale, cldr, ldml))
+
+	// AC is an exceptional reservation, CD only has a variant name
+	codes := []string{}
+	for code := range locale.Territories {
+		codes = append(codes, code)
+	}
+
+	assert.ElementsMatch(t, []string{"AD", "CF", "CG", "HK", "HM", "HN", "IO"}, codes)
+}
+
+func Test_Load_Locale_Handler_Error(t *testing.T) {
+	ldml, err := LoadLdml("fixtures/ldml_main.xml")
+	assert.NoError(t, err)
+
+	cldr := GetCLDR(t)
+	cldr.LdmlHandlers = []LdmlHandler{func(locale *Locale, cldr *CLDR, ldml *Ldml) error {
+		return errors.New("invalid document")
+	}}
+
+	locale, err := LoadLocale(cldr, ldml)
+	assert.Nil(t, locale)
+	assert.EqualError(t, err, "invalid document")
+}
+
+func Test_Load_Locale_Missing_Parent(t *testing.T) {
+	ldml, err := LoadLdml("fixtures/ldml_inherited.xml")
+	assert.NoError(t, err)
+
+	// fr_YT requires the fr locale
+	locale, err := LoadLocale(GetCLDR(t), ldml)
+	assert.Nil(t, locale)
+	assert.EqualError(t, err, "the parent locale fr of the locale fr_YT is not loaded")
+}
 
-	assert.Len(t, locale.Territories, 8)
+func Test_Attach_Plurals(t *testing.T) {
+	cldr := &CLDR{
+		Plurals: map[string][]*PluralRule{
+			"root":  {{Count: "other"}},
+			"pt":    {{Count: "one", Rule: "i = 0..1"}},
+			"pt_PT": {{Count: "one", Rule: "i = 1 and v = 0"}},
+			"sr":    {{Count: "one", Rule: "v = 0 and i % 10 = 1"}},
+		},
+	}
+
+	root := &Locale{IsRoot: true, Code: "root"}
+	pt := &Locale{IsBase: true, Code: "pt", Language: "pt", Parent: root}
+
+	for _, c := range []struct {
+		locale   *Locale
+		expected []*PluralRule
+	}{
+		{pt, cldr.Plurals["pt"]},
+		// the territory locale defines its rules
+		{&Locale{Code: "pt_PT", Language: "pt", Parent: pt}, cldr.Plurals["pt_PT"]},
+		// the rules are inherited from the parent
+		{&Locale{Code: "pt_BR", Language: "pt", Parent: pt}, nil},
+		// the locale inherits from root, the rules of the language are used
+		{&Locale{Code: "sr_Latn", Language: "sr", Parent: root}, cldr.Plurals["sr"]},
+	} {
+		assert.NoError(t, AttachPlurals(c.locale, cldr, &Ldml{}))
+		assert.Equal(t, c.expected, c.locale.PluralRules, c.locale.Code)
+	}
 }
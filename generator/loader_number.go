@@ -3,9 +3,10 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 )
@@ -41,9 +42,13 @@ func processPattern(pattern string) string {
 	return pattern
 }
 
-func AttachNumber(locale *Locale, cldr *CLDR, ldml *Ldml) {
+func AttachNumber(locale *Locale, cldr *CLDR, ldml *Ldml) error {
+	minimumGroupingDigits, err := ifEmptyInt(ldml.Numbers.MinimumGroupingDigits, "1")
+	if err != nil {
+		return fmt.Errorf("invalid minimumGroupingDigits: %w", err)
+	}
 
-	locale.Number.MinimumGroupingDigits = ifEmptyInt(ldml.Numbers.MinimumGroupingDigits, "1")
+	locale.Number.MinimumGroupingDigits = minimumGroupingDigits
 	locale.Number.DefaultNumberSystem = ldml.Numbers.DefaultNumberingSystem
 
 	// Parent must have a valid configuration
@@ -51,10 +56,18 @@ func AttachNumber(locale *Locale, cldr *CLDR, ldml *Ldml) {
 		locale.Number.DefaultNumberSystem = locale.Parent.Number.DefaultNumberSystem
 	}
 
-	AttachNumberSymbols(locale, cldr, ldml)
-	AttachNumberDecimals(locale, cldr, ldml)
-	AttachNumberPercent(locale, cldr, ldml)
-	AttachNumberCurrencies(locale, cldr, ldml)
+	for _, attach := range []LdmlHandler{
+		AttachNumberSymbols,
+		AttachNumberDecimals,
+		AttachNumberPercent,
+		AttachNumberCurrencies,
+	} {
+		if err := attach(locale, cldr, ldml); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // This function an adaptation of https://github.com/bojanz/currency
@@ -84,7 +97,7 @@ func AttachPattern(format *NumberFormat) {
 	format.StandardPattern = processPattern(format.Pattern)
 }
 
-func AttachNumberPercent(locale *Locale, cldr *CLDR, ldml *Ldml) {
+func AttachNumberPercent(locale *Locale, cldr *CLDR, ldml *Ldml) error {
 	for _, t := range ldml.Numbers.PercentFormats {
 		// no symbol is defined, so we skip
 		if t.NumberSystem == "" {
@@ -110,4 +123,6 @@ func AttachNumberPercent(locale *Locale, cldr *CLDR, ldml *Ldml) {
 
 		locale.Number.Percents[t.NumberSystem]["default"] = append(locale.Number.Percents[t.NumberSystem]["default"], format)
 	}
+
+	return nil
 }
@@ -3,7 +3,7 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 // <currencyFormats numberSystem="latn">
 //   <currencyFormatLength> == default if not defined
@@ -14,7 +14,7 @@ package main
 //           <pattern>#,##0.00 ¤;(#,##0.00 ¤)</pattern>
 //           <pattern alt="noCurrency">#,##0.00;(#,##0.00)</pattern>
 //       </currencyFormat>
-//       <! -- Alternative with an alias --> Skipping to use the standard format
+//       <! -- Alternative with an alias --> resolved with the standard format
 //       <currencyFormat type="accounting">
 //          <alias source="locale" path="../currencyFormat[@type='standard']"/>
 //       </currencyFormat>
@@ -36,7 +36,7 @@ package main
 //   <unitPattern count="other">{0} {1}</unitPattern>
 // </currencyFormats>
 
-func AttachNumberCurrencies(locale *Locale, cldr *CLDR, ldml *Ldml) {
+func AttachNumberCurrencies(locale *Locale, cldr *CLDR, ldml *Ldml) error {
 	// <currencyFormats numberSystem="latn">
 	for _, cfs := range ldml.Numbers.CurrencyFormats {
 		// no symbol is defined, so we skip
@@ -44,10 +44,6 @@ func AttachNumberCurrencies(locale *Locale, cldr *CLDR, ldml *Ldml) {
 			continue
 		}
 
-		if cfs.Alias.Source != "" {
-			continue
-		}
-
 		// <currencyFormatLength>
 		for _, cfl := range cfs.CurrencyFormatLength {
 			code := ifEmptyString(cfl.Type, "default")
@@ -77,4 +73,6 @@ func AttachNumberCurrencies(locale *Locale, cldr *CLDR, ldml *Ldml) {
 			}
 		}
 	}
+
+	return nil
 }
@@ -3,11 +3,11 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 type FormatGroup map[string][]*NumberFormat
 
-func AttachNumberDecimals(locale *Locale, cldr *CLDR, ldml *Ldml) {
+func AttachNumberDecimals(locale *Locale, cldr *CLDR, ldml *Ldml) error {
 	for _, t := range ldml.Numbers.DecimalFormats {
 		// no symbol is defined, so we skip
 		if t.NumberSystem == "" {
@@ -18,12 +18,6 @@ func AttachNumberDecimals(locale *Locale, cldr *CLDR, ldml *Ldml) {
 		for _, f := range t.DecimalFormatLength {
 			code := ifEmptyString(f.Type, "default")
 
-			// this is an alias, pointing to the latn (to check by evaluating the path)
-			// ignoring the path for now.
-			if t.Alias.Source != "" {
-				continue
-			}
-
 			// a code is defined in the locale, so we need to override the default values
 			if locale.Number.Decimals[t.NumberSystem] == nil {
 				locale.Number.Decimals[t.NumberSystem] = FormatGroup{}
@@ -43,4 +37,6 @@ func AttachNumberDecimals(locale *Locale, cldr *CLDR, ldml *Ldml) {
 			}
 		}
 	}
+
+	return nil
 }
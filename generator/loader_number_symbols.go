@@ -3,7 +3,7 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import "fmt"
 
@@ -24,7 +24,7 @@ type Symbol struct {
 	PerMilleSign           string
 }
 
-func AttachNumberSymbols(locale *Locale, cldr *CLDR, ldml *Ldml) {
+func AttachNumberSymbols(locale *Locale, cldr *CLDR, ldml *Ldml) error {
 	var defaultNumber *Symbol
 
 	// 1 - we need to find the default number system: latn is the default number system
@@ -60,7 +60,7 @@ func AttachNumberSymbols(locale *Locale, cldr *CLDR, ldml *Ldml) {
 		defaultNumber = locale.Parent.Number.Symbols[locale.Number.DefaultNumberSystem]
 	} else {
 		fmt.Printf("No default number system found for %s\n", locale.Code)
-		return
+		return nil
 	}
 
 	// if any value are defined in the locale, there are overriding the default values
@@ -92,4 +92,6 @@ func AttachNumberSymbols(locale *Locale, cldr *CLDR, ldml *Ldml) {
 
 		locale.Number.Symbols[t.NumberSystem] = number
 	}
+
+	return nil
 }
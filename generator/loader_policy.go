@@ -0,0 +1,52 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"slices"
+)
+
+// DraftLevels are the CLDR draft statuses, from the lowest to the highest
+// one, a value without draft attribute is approved.
+// http://www.unicode.org/reports/tr35/#Attribute_draft
+var DraftLevels = []string{"unconfirmed", "provisional", "contributed", "approved"}
+
+// DefaultDraftLevel is the minimal draft status of the generated values,
+// this is the level used by ICU.
+const DefaultDraftLevel = "contributed"
+
+// IsDraftIncluded returns true if a value with the draft status is at or
+// above the draft level of the CLDR.
+func (cldr *CLDR) IsDraftIncluded(draft string) bool {
+	if draft == "" {
+		return true
+	}
+
+	return slices.Index(DraftLevels, draft) >= slices.Index(DraftLevels, ifEmptyString(cldr.DraftLevel, DefaultDraftLevel))
+}
+
+// IsAltIncluded returns true if the alt variant of a display name is kept,
+// all the variants are kept if no alt is configured.
+func (cldr *CLDR) IsAltIncluded(alt string) bool {
+	return len(cldr.Alts) == 0 || slices.Contains(cldr.Alts, alt)
+}
+
+// RemoveDrafts removes the elements below the draft level of the CLDR, the
+// children of a removed element are removed too.
+func (n *XmlNode) RemoveDrafts(cldr *CLDR) {
+	nodes := []*XmlNode{}
+
+	for _, c := range n.Nodes {
+		if !cldr.IsDraftIncluded(c.GetAttr("draft")) {
+			continue
+		}
+
+		c.RemoveDrafts(cldr)
+		nodes = append(nodes, c)
+	}
+
+	n.Nodes = nodes
+}
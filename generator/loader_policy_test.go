@@ -0,0 +1,73 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Draft_Level(t *testing.T) {
+	cldr := &CLDR{DraftLevel: "contributed"}
+
+	assert.True(t, cldr.IsDraftIncluded(""))
+	assert.True(t, cldr.IsDraftIncluded("approved"))
+	assert.True(t, cldr.IsDraftIncluded("contributed"))
+	assert.False(t, cldr.IsDraftIncluded("provisional"))
+	assert.False(t, cldr.IsDraftIncluded("unconfirmed"))
+
+	cldr.DraftLevel = "unconfirmed"
+	assert.True(t, cldr.IsDraftIncluded("unconfirmed"))
+}
+
+func Test_Remove_Drafts(t *testing.T) {
+	doc := &XmlNode{}
+	assert.Nil(t, xml.Unmarshal([]byte(`<ldml><localeDisplayNames><territories>
+		<territory type="FR">France</territory>
+		<territory type="GB" draft="contributed">Royaume-Uni</territory>
+		<territory type="US" draft="unconfirmed">États-Unis</territory>
+	</territories></localeDisplayNames></ldml>`), doc))
+
+	doc.RemoveDrafts(&CLDR{DraftLevel: "contributed"})
+
+	territories := doc.Find(XPath{{Name: "localeDisplayNames", Attrs: map[string]string{}}, {Name: "territories", Attrs: map[string]string{}}})
+	assert.Len(t, territories.Nodes, 2)
+	assert.Equal(t, "GB", territories.Nodes[1].GetAttr("type"))
+}
+
+func Test_Attach_Territories_Alts(t *testing.T) {
+	ldml := &Ldml{}
+	assert.Nil(t, xml.Unmarshal([]byte(`<ldml><localeDisplayNames><territories>
+		<territory type="GB">Royaume-Uni</territory>
+		<territory type="GB" alt="short">R.-U.</territory>
+		<territory type="GB" alt="variant">Grande-Bretagne</territory>
+		<territory type="US" alt="short">É.-U.</territory>
+	</territories></localeDisplayNames></ldml>`), ldml))
+
+	cldr := &CLDR{
+		Validities: []*Validity{{From: "region", Status: "regular", List: []string{"GB", "US"}}},
+		RootLocale: &Locale{Territories: map[string]*Territory{
+			"GB": {Code: "GB", Alpha3: "GBR", Numeric: "826"},
+			"US": {Code: "US", Alpha3: "USA", Numeric: "840"},
+		}},
+	}
+
+	locale := &Locale{}
+	assert.NoError(t, AttachTerritories(locale, cldr, ldml))
+
+	assert.Equal(t, "Royaume-Uni", locale.Territories["GB"].Name)
+	assert.Equal(t, map[string]string{"short": "R.-U.", "variant": "Grande-Bretagne"}, locale.Territories["GB"].Alts)
+
+	// an alt name is not kept without the name
+	assert.Nil(t, locale.Territories["US"])
+
+	cldr.Alts = []string{"short"}
+	assert.NoError(t, AttachTerritories(locale, cldr, ldml))
+
+	assert.Equal(t, map[string]string{"short": "R.-U."}, locale.Territories["GB"].Alts)
+}
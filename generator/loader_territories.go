@@ -3,7 +3,7 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import (
 	"fmt"
@@ -15,10 +15,11 @@ import (
 type Territory struct {
 	Code    string
 	Name    string
-	Alt     string
 	Numeric string
 	Alpha3  string
 	Const   string
+	// alt => name, ie: short => US
+	Alts map[string]string
 }
 
 var TerritoriesDenyList = map[string]bool{
@@ -69,14 +70,15 @@ var TerritoriesWithdrawnCodes = []string{
 	"891", //	Serbia and Montenegro
 }
 
-func AttachTerritories(locale *Locale, cldr *CLDR, ldml *Ldml) {
+func AttachTerritories(locale *Locale, cldr *CLDR, ldml *Ldml) error {
 	var territories map[string]*Territory = map[string]*Territory{}
+	var alts map[string]map[string]string = map[string]map[string]string{}
 
 	list := cldr.GetValidity("region", "regular")
 
 	if list == nil {
 		fmt.Printf("No regions found\n")
-		return
+		return nil
 	}
 
 	if locale.IsRoot {
@@ -97,7 +99,7 @@ func AttachTerritories(locale *Locale, cldr *CLDR, ldml *Ldml) {
 			locale.Territories[t.Code].Const = fmt.Sprintf("Region_%s", strings.ToUpper(t.Code))
 		}
 
-		return
+		return nil
 	}
 
 	for _, t := range ldml.LocaleDisplayNames.Territories.Territory {
@@ -117,24 +119,40 @@ func AttachTerritories(locale *Locale, cldr *CLDR, ldml *Ldml) {
 			continue
 		}
 
-		// we don't keep variants or short names.
-		if t.Alt != "" {
+		if _, err := strconv.Atoi(t.Type); err == nil {
 			continue
 		}
 
-		if _, err := strconv.Atoi(t.Type); err == nil {
+		// the variants and short names, ie: "UK" for GB
+		if t.Alt != "" {
+			if cldr.IsAltIncluded(t.Alt) {
+				if alts[t.Type] == nil {
+					alts[t.Type] = map[string]string{}
+				}
+
+				alts[t.Type][t.Alt] = t.Text
+			}
+
 			continue
 		}
 
 		territories[t.Type] = &Territory{
 			Code:    t.Type,
 			Name:    t.Text,
-			Alt:     t.Alt,
 			Alpha3:  cldr.RootLocale.Territories[t.Type].Alpha3,
 			Numeric: cldr.RootLocale.Territories[t.Type].Numeric,
 			Const:   fmt.Sprintf("Region_%s", strings.ToUpper(t.Type)),
 		}
 	}
 
+	// an alt name is only kept with the name of the territory
+	for code, names := range alts {
+		if territory, ok := territories[code]; ok {
+			territory.Alts = names
+		}
+	}
+
 	locale.Territories = territories
+
+	return nil
 }
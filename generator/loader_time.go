@@ -3,10 +3,11 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -22,6 +23,7 @@ type TimeFormat struct {
 	Hour           string
 	Gmt            string
 	GmtZero        string
+	Region         string
 	RegionDaylight string
 	RegionStandard string
 }
@@ -37,16 +39,21 @@ var TimezoneDenyList = map[string]bool{
 	"PST8PDT":     true,
 }
 
-func AttachTimeFormat(locale *Locale, cldr *CLDR, ldml *Ldml) {
+func AttachTimeFormat(locale *Locale, cldr *CLDR, ldml *Ldml) error {
 	timeFormats := &TimeFormat{
 		Hour:           ldml.Dates.TimeZoneNames.HourFormat,
 		Gmt:            ldml.Dates.TimeZoneNames.GmtFormat,
 		GmtZero:        ldml.Dates.TimeZoneNames.GmtZeroFormat,
+		Region:         "",
 		RegionDaylight: "",
 		RegionStandard: "",
 	}
 
 	for _, t := range ldml.Dates.TimeZoneNames.RegionFormat {
+		if t.Type == "" {
+			timeFormats.Region = t.Text
+		}
+
 		if t.Type == "daylight" {
 			timeFormats.RegionDaylight = t.Text
 		}
@@ -57,6 +64,8 @@ func AttachTimeFormat(locale *Locale, cldr *CLDR, ldml *Ldml) {
 	}
 
 	locale.TimeFormat = timeFormats
+
+	return nil
 }
 
 // This implementation is wrong
@@ -66,7 +75,7 @@ func AttachTimeFormat(locale *Locale, cldr *CLDR, ldml *Ldml) {
 //  3. Also some name can be duplicated, so we need to append the city name
 //  4. Some Ldml does not have any timezone configured, in this case we can
 //     delete all timezones - the parent locale will be used
-func AttachTimeZones(locale *Locale, cldr *CLDR, ldml *Ldml) {
+func AttachTimeZones(locale *Locale, cldr *CLDR, ldml *Ldml) error {
 	var timezones map[string]*TimeZone = map[string]*TimeZone{}
 
 	var replacer = strings.NewReplacer("/", "_", "-", "_")
@@ -124,8 +133,95 @@ func AttachTimeZones(locale *Locale, cldr *CLDR, ldml *Ldml) {
 	}
 
 	if !overwrites && !locale.IsRoot {
-		return
+		return nil
 	}
 
 	locale.TimeZones = timezones
+
+	return nil
+}
+
+// AttachZoneNames loads the localized names of the metazones and the names
+// specific to a zone, the keys are <width>_<type>, ie: long_standard or
+// short_daylight. The zone names also contain the exemplar city.
+// The root locale holds the metazone used by each time zone.
+func AttachZoneNames(locale *Locale, cldr *CLDR, ldml *Ldml) error {
+	add := func(names map[string]map[string]string, code, key, value, draft string) {
+		// the draft values are not reviewed
+		if value == "" || draft != "" {
+			return
+		}
+
+		if _, ok := names[code]; !ok {
+			names[code] = map[string]string{}
+		}
+
+		names[code][key] = value
+	}
+
+	for _, m := range ldml.Dates.TimeZoneNames.Metazone {
+		add(locale.MetaZoneNames, m.Type, "long_generic", m.Long.Generic.Text, m.Long.Generic.Draft)
+		add(locale.MetaZoneNames, m.Type, "long_standard", m.Long.Standard.Text, m.Long.Standard.Draft)
+		add(locale.MetaZoneNames, m.Type, "long_daylight", m.Long.Daylight.Text, m.Long.Daylight.Draft)
+		add(locale.MetaZoneNames, m.Type, "short_generic", m.Short.Generic.Text, m.Short.Generic.Draft)
+		add(locale.MetaZoneNames, m.Type, "short_standard", m.Short.Standard.Text, m.Short.Standard.Draft)
+		add(locale.MetaZoneNames, m.Type, "short_daylight", m.Short.Daylight.Text, m.Short.Daylight.Draft)
+	}
+
+	for _, z := range ldml.Dates.TimeZoneNames.Zone {
+		add(locale.ZoneNames, z.Type, "city", z.ExemplarCity.Text, "")
+		add(locale.ZoneNames, z.Type, "long_generic", z.Long.Generic, "")
+		add(locale.ZoneNames, z.Type, "long_standard", z.Long.Standard, "")
+		add(locale.ZoneNames, z.Type, "long_daylight", z.Long.Daylight, "")
+		add(locale.ZoneNames, z.Type, "short_generic", z.Short.Generic.Text, z.Short.Generic.Draft)
+		add(locale.ZoneNames, z.Type, "short_standard", z.Short.Standard.Text, z.Short.Standard.Draft)
+		add(locale.ZoneNames, z.Type, "short_daylight", z.Short.Daylight.Text, z.Short.Daylight.Draft)
+	}
+
+	if !locale.IsRoot {
+		return nil
+	}
+
+	for _, m := range cldr.MetaZones {
+		if m.Zone != "" {
+			locale.MetaZones[m.Type] = m.Zone
+		}
+	}
+
+	locale.ZoneAliases = cldr.ZoneAliases
+	locale.ZoneIds = cldr.ZoneIds
+
+	return nil
+}
+
+// AttachZoneAliases attaches the aliases of the time zones, the first alias
+// of a bcp47 time zone is the CLDR id: Asia/Calcutta for Asia/Kolkata. The
+// IANA ids renamed after the CLDR ones, like Europe/Kyiv, are aliases. The
+// name of the type is the short id of the CLDR id: frpar for Europe/Paris.
+func AttachZoneAliases(cldr *CLDR, bcp47 *LdmlBcp47) error {
+	for _, key := range bcp47.Keyword.Key {
+		if key.Name != "tz" {
+			continue
+		}
+
+		for _, t := range key.Type {
+			aliases := strings.Fields(t.Alias)
+			if len(aliases) == 0 {
+				continue
+			}
+
+			cldr.ZoneIds[aliases[0]] = t.Name
+
+			// the iana attribute is only defined if the id is not an alias
+			if t.Iana != "" && !slices.Contains(aliases, t.Iana) {
+				aliases = append(aliases, t.Iana)
+			}
+
+			for _, alias := range aliases[1:] {
+				cldr.ZoneAliases[alias] = aliases[0]
+			}
+		}
+	}
+
+	return nil
 }
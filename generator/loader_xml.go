@@ -3,19 +3,37 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-package main
+package generator
 
 import (
 	"encoding/xml"
-	"regexp"
 )
 
+// LdmlBcp47 is a file of the bcp47 folder, ie: bcp47/timezone.xml
+type LdmlBcp47 struct {
+	XMLName xml.Name `xml:"ldmlBCP47"`
+	Keyword struct {
+		Key []struct {
+			Name string `xml:"name,attr"`
+			Type []struct {
+				Name       string `xml:"name,attr"`
+				Alias      string `xml:"alias,attr"`
+				Deprecated string `xml:"deprecated,attr"`
+				Iana       string `xml:"iana,attr"`
+			} `xml:"type"`
+		} `xml:"key"`
+	} `xml:"keyword"`
+}
+
 type SupplementalData struct {
 	XMLName xml.Name `xml:"supplementalData"`
 	Text    string   `xml:",chardata"`
+	// the elements not decoded by the struct, ie: the data of a plugin file
+	Nodes   []*XmlNode `xml:",any"`
 	Version struct {
-		Text   string `xml:",chardata"`
-		Number string `xml:"number,attr"`
+		Text        string `xml:",chardata"`
+		Number      string `xml:"number,attr"`
+		CldrVersion string `xml:"cldrVersion,attr"`
 	} `xml:"version"`
 
 	IdValidity struct {
@@ -173,6 +191,14 @@ type SupplementalData struct {
 			Ordering    string `xml:"ordering,attr"`
 		} `xml:"calendarPreference"`
 	} `xml:"calendarPreferenceData"`
+	LikelySubtags struct {
+		Text         string `xml:",chardata"`
+		LikelySubtag []struct {
+			Text string `xml:",chardata"`
+			From string `xml:"from,attr"`
+			To   string `xml:"to,attr"`
+		} `xml:"likelySubtag"`
+	} `xml:"likelySubtags"`
 	WeekData struct {
 		Text    string `xml:",chardata"`
 		MinDays []struct {
@@ -250,6 +276,29 @@ type SupplementalData struct {
 			Locales string `xml:"locales,attr"`
 		} `xml:"parentLocale"`
 	} `xml:"parentLocales"`
+	LanguageMatching struct {
+		Text            string `xml:",chardata"`
+		LanguageMatches []struct {
+			Text            string `xml:",chardata"`
+			Type            string `xml:"type,attr"`
+			ParadigmLocales struct {
+				Text    string `xml:",chardata"`
+				Locales string `xml:"locales,attr"`
+			} `xml:"paradigmLocales"`
+			MatchVariable []struct {
+				Text  string `xml:",chardata"`
+				ID    string `xml:"id,attr"`
+				Value string `xml:"value,attr"`
+			} `xml:"matchVariable"`
+			LanguageMatch []struct {
+				Text      string `xml:",chardata"`
+				Desired   string `xml:"desired,attr"`
+				Supported string `xml:"supported,attr"`
+				Distance  string `xml:"distance,attr"`
+				Oneway    string `xml:"oneway,attr"`
+			} `xml:"languageMatch"`
+		} `xml:"languageMatches"`
+	} `xml:"languageMatching"`
 	PersonNamesDefaults struct {
 		Text                    string `xml:",chardata"`
 		NameOrderLocalesDefault []struct {
@@ -266,6 +315,20 @@ type SupplementalData struct {
 		} `xml:"reference"`
 	} `xml:"references"`
 
+	// -- plurals.xml
+	Plurals []struct {
+		Text        string `xml:",chardata"`
+		Type        string `xml:"type,attr"`
+		PluralRules []struct {
+			Text       string `xml:",chardata"`
+			Locales    string `xml:"locales,attr"`
+			PluralRule []struct {
+				Text  string `xml:",chardata"`
+				Count string `xml:"count,attr"`
+			} `xml:"pluralRule"`
+		} `xml:"pluralRules"`
+	} `xml:"plurals"`
+
 	DayPeriodRuleSet []struct {
 		Text           string `xml:",chardata"`
 		Type           string `xml:"type,attr"`
@@ -325,6 +388,14 @@ type Ldml struct {
 			Text string `xml:",chardata"`
 			Type string `xml:"type,attr"`
 		} `xml:"language"`
+		Script struct {
+			Text string `xml:",chardata"`
+			Type string `xml:"type,attr"`
+		} `xml:"script"`
+		Variant struct {
+			Text string `xml:",chardata"`
+			Type string `xml:"type,attr"`
+		} `xml:"variant"`
 	} `xml:"identity"`
 	LocaleDisplayNames struct {
 		LocaleDisplayPattern struct {
@@ -440,6 +511,7 @@ type Ldml struct {
 							Text  string `xml:",chardata"`
 							Type  string `xml:"type,attr"`
 							Draft string `xml:"draft,attr"`
+							Alt   string `xml:"alt,attr"`
 						} `xml:"era"`
 					} `xml:"eraNarrow"`
 				} `xml:"eras"`
@@ -454,8 +526,9 @@ type Ldml struct {
 						} `xml:"dateFormatItem"`
 					} `xml:"availableFormats"`
 					IntervalFormats struct {
-						Text               string `xml:",chardata"`
-						IntervalFormatItem []struct {
+						Text                   string `xml:",chardata"`
+						IntervalFormatFallback string `xml:"intervalFormatFallback"`
+						IntervalFormatItem     []struct {
 							Text               string `xml:",chardata"`
 							ID                 string `xml:"id,attr"`
 							GreatestDifference []struct {
@@ -526,7 +599,7 @@ type Ldml struct {
 				} `xml:"days"`
 				Quarters struct {
 					Text           string `xml:",chardata"`
-					QuarterContext struct {
+					QuarterContext []struct {
 						Text         string `xml:",chardata"`
 						Type         string `xml:"type,attr"`
 						QuarterWidth []struct {
@@ -535,6 +608,7 @@ type Ldml struct {
 							Quarter []struct {
 								Text string `xml:",chardata"`
 								Type string `xml:"type,attr"`
+								Alt  string `xml:"alt,attr"`
 							} `xml:"quarter"`
 						} `xml:"quarterWidth"`
 					} `xml:"quarterContext"`
@@ -611,6 +685,7 @@ type Ldml struct {
 				} `xml:"exemplarCity"`
 				Long struct {
 					Text     string `xml:",chardata"`
+					Generic  string `xml:"generic"`
 					Standard string `xml:"standard"`
 					Daylight string `xml:"daylight"`
 				} `xml:"long"`
@@ -906,15 +981,3 @@ type Ldml struct {
 		} `xml:"sampleName"`
 	} `xml:"personNames"`
 }
-
-// This implementation is quite fragile, but it works for now.
-func ReadAlias(alias string) []string {
-	re := regexp.MustCompile("type='([a-z]*)'")
-	result := re.FindAllStringSubmatch(alias, -1)
-
-	var types []string
-	for _, r := range result {
-		types = append(types, r[1])
-	}
-	return types
-}
@@ -0,0 +1,205 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// LocaleError is the failure of a locale, the locales inheriting from a
+// failed locale fail too.
+type LocaleError struct {
+	Code string
+	Err  error
+}
+
+func (e *LocaleError) Error() string {
+	return e.Code + ": " + e.Err.Error()
+}
+
+func (e *LocaleError) Unwrap() error {
+	return e.Err
+}
+
+// Pipeline loads the locales with a bounded pool of workers. A locale is
+// loaded after its parent, so the locales are loaded level by level: the
+// children of root, then their children, ... The locales of a level only
+// read the loaded locales, they are added to the CLDR once the level is
+// done.
+type Pipeline struct {
+	// the number of locales loaded at the same time, the number of CPUs by
+	// default
+	Workers int
+	// the locales not inheriting from a failed locale are still loaded
+	ContinueOnError bool
+	// called by the workers once a locale is loaded, ie: to write it
+	Loaded func(locale *Locale) error
+}
+
+// Run loads the locales and their parents, the loaded locales are skipped.
+// The failures are returned sorted by code.
+func (p *Pipeline) Run(cldr *CLDR, codes []string) []*LocaleError {
+	loaded := p.Loaded
+	if loaded == nil {
+		loaded = func(locale *Locale) error { return nil }
+	}
+
+	if cldr.RootLocale == nil {
+		root, err := loadLocale(cldr, "root", loaded)
+		if err != nil {
+			return []*LocaleError{{Code: "root", Err: err}}
+		}
+
+		cldr.RootLocale = root
+	}
+
+	levels := cldr.getLocaleLevels(codes)
+
+	errs := []*LocaleError{}
+	failed := map[string]bool{}
+
+	for _, level := range levels {
+		locales := make([]*Locale, len(level))
+		levelErrs := make([]error, len(level))
+
+		p.each(len(level), func(i int) {
+			if parent := cldr.GetParentCode(level[i]); failed[parent] {
+				levelErrs[i] = fmt.Errorf("the parent locale %s failed", parent)
+				return
+			}
+
+			locales[i], levelErrs[i] = loadLocale(cldr, level[i], loaded)
+		})
+
+		for i, code := range level {
+			if levelErrs[i] != nil {
+				failed[code] = true
+				errs = append(errs, &LocaleError{Code: code, Err: levelErrs[i]})
+				continue
+			}
+
+			cldr.Locales[locales[i].Code] = locales[i]
+		}
+
+		if len(errs) > 0 && !p.ContinueOnError {
+			break
+		}
+	}
+
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Code < errs[j].Code
+	})
+
+	return errs
+}
+
+// each calls the task for the indexes from 0 to n with the workers.
+func (p *Pipeline) each(n int, task func(i int)) {
+	workers := p.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+
+	indexes := make(chan int)
+
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				task(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+}
+
+// loadLocale loads the locale from the main folder, the loaders return
+// their errors, the recover is a last resort guard so a panic fails the
+// locale, not the generation.
+func loadLocale(cldr *CLDR, code string, loaded func(locale *Locale) error) (locale *Locale, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			locale, err = nil, fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	if locale, err = LoadLocaleFromFile("main/"+code+".xml", cldr); err != nil {
+		return nil, err
+	}
+
+	if err = loaded(locale); err != nil {
+		return nil, err
+	}
+
+	return locale, nil
+}
+
+// getLocaleLevels returns the codes of the locales to load and of their
+// parents, grouped by their depth from root and sorted.
+func (cldr *CLDR) getLocaleLevels(codes []string) [][]string {
+	depths := map[string]int{}
+
+	var depth func(code string) int
+	depth = func(code string) int {
+		if code == "root" {
+			return 0
+		}
+
+		if d, ok := depths[code]; ok {
+			return d
+		}
+
+		depths[code] = depth(cldr.GetParentCode(code)) + 1
+
+		return depths[code]
+	}
+
+	for _, code := range codes {
+		depth(code)
+	}
+
+	levels := [][]string{}
+	for code, d := range depths {
+		if _, ok := cldr.Locales[code]; ok {
+			continue
+		}
+
+		for len(levels) < d {
+			levels = append(levels, []string{})
+		}
+
+		levels[d-1] = append(levels[d-1], code)
+	}
+
+	for _, level := range levels {
+		sort.Strings(level)
+	}
+
+	return levels
+}
+
+// WriteLocaleErrors writes the failed locales.
+func WriteLocaleErrors(w io.Writer, errs []*LocaleError) {
+	fmt.Fprintf(w, "%d locales failed\n", len(errs))
+
+	for _, err := range errs {
+		fmt.Fprintf(w, "  [%s] %s\n", err.Code, err.Err.Error())
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getPipelineCLDR(t *testing.T) *CLDR {
+	cldr, err := LoadCLDR("fixtures/core.zip")
+	assert.NoError(t, err)
+
+	t.Cleanup(func() { cldr.Close() })
+
+	return cldr
+}
+
+func Test_Pipeline_Run(t *testing.T) {
+	cldr := getPipelineCLDR(t)
+
+	assert.Equal(t, [][]string{{"fr"}, {"fr_CA"}}, cldr.getLocaleLevels([]string{"fr_CA", "fr"}))
+
+	mutex := sync.Mutex{}
+	codes := []string{}
+
+	pipeline := &Pipeline{
+		Workers: 4,
+		Loaded: func(locale *Locale) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			codes = append(codes, locale.Code)
+
+			return nil
+		},
+	}
+
+	errs := pipeline.Run(cldr, []string{"fr_CA"})
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"root", "fr", "fr_CA"}, codes)
+	assert.Equal(t, cldr.Locales["fr"], cldr.Locales["fr_CA"].Parent)
+
+	// the loaded locales are skipped
+	errs = pipeline.Run(cldr, []string{"fr_CA"})
+	assert.Empty(t, errs)
+	assert.Len(t, codes, 3)
+}
+
+func Test_Pipeline_Errors(t *testing.T) {
+	cldr := getPipelineCLDR(t)
+
+	// de is not defined, fr fails when written
+	written := errors.New("unable to write")
+
+	pipeline := &Pipeline{
+		Workers: 2,
+		Loaded: func(locale *Locale) error {
+			if locale.Code == "fr" {
+				return written
+			}
+
+			return nil
+		},
+	}
+
+	errs := pipeline.Run(cldr, []string{"de", "fr_CA"})
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "de", errs[0].Code)
+	assert.ErrorIs(t, errs[0], os.ErrNotExist)
+	assert.Equal(t, "fr", errs[1].Code)
+	assert.ErrorIs(t, errs[1], written)
+	assert.NotContains(t, cldr.Locales, "fr_CA")
+
+	// the other locales are loaded, the children of a failed locale fail too
+	cldr = getPipelineCLDR(t)
+	pipeline.ContinueOnError = true
+
+	errs = pipeline.Run(cldr, []string{"de", "fr_CA", "sr_Latn"})
+	codes := []string{}
+	for _, err := range errs {
+		codes = append(codes, err.Code)
+	}
+
+	assert.Equal(t, []string{"de", "fr", "fr_CA", "sr_Latn"}, codes)
+	assert.Equal(t, "the parent locale fr failed", errs[2].Err.Error())
+
+	buffer := bytes.NewBuffer([]byte{})
+	WriteLocaleErrors(buffer, errs[2:3])
+	assert.Equal(t, "1 locales failed\n  [fr_CA] the parent locale fr failed\n", buffer.String())
+}
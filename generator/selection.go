@@ -0,0 +1,179 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// CoverageLevels are the CLDR coverage levels, from the lowest to the
+// highest one.
+var CoverageLevels = []string{"core", "basic", "moderate", "modern", "comprehensive"}
+
+// ErrNoFS is returned when the CLDR files are not opened, see LoadCLDR.
+var ErrNoFS = errors.New("the CLDR files are not opened")
+
+// Selection defines the locales to generate, the locales matching the
+// explicit codes, the globs or all the available locales are kept if their
+// coverage level is at least the Coverage one.
+type Selection struct {
+	Locales  []string
+	Globs    []string
+	All      bool
+	Coverage string
+}
+
+// LoadCoverageLevels loads the coverage level targeted by each locale, the
+// file contains lines like "fr ; modern ; French". The path rules defined
+// in supplemental/coverageLevels.xml do not contain the locale levels.
+func LoadCoverageLevels(fsys fs.FS, name string) (map[string]string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	levels := map[string]string{}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.Split(line, ";")
+		if len(parts) < 2 {
+			continue
+		}
+
+		levels[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return levels, scanner.Err()
+}
+
+// GetCoverageLevel returns the coverage level of the locale, a locale
+// without level uses the level of its language: fr_CA is fr.
+func (cldr *CLDR) GetCoverageLevel(code string) string {
+	if level, ok := cldr.CoverageLevels[code]; ok {
+		return level
+	}
+
+	language, _, _ := strings.Cut(code, "_")
+
+	return cldr.CoverageLevels[language]
+}
+
+// GetAvailableLocales returns the codes of the locales defined in the main
+// folder, root excepted.
+func (cldr *CLDR) GetAvailableLocales() ([]string, error) {
+	if cldr.FS == nil {
+		return nil, ErrNoFS
+	}
+
+	files, err := fs.Glob(cldr.FS, "main/*.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	codes := []string{}
+	for _, file := range files {
+		code := strings.TrimSuffix(path.Base(file), ".xml")
+		if code == "root" {
+			continue
+		}
+
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+// HasLocale returns true if the locale is defined in the main folder.
+func (cldr *CLDR) HasLocale(code string) (bool, error) {
+	if cldr.FS == nil {
+		return false, ErrNoFS
+	}
+
+	_, err := fs.Stat(cldr.FS, "main/"+code+".xml")
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// SelectLocales returns the sorted codes of the locales to generate.
+func SelectLocales(cldr *CLDR, selection *Selection) ([]string, error) {
+	minLevel := -1
+	if selection.Coverage != "" {
+		if minLevel = slices.Index(CoverageLevels, selection.Coverage); minLevel == -1 {
+			return nil, fmt.Errorf("unknown coverage level %q, expected one of %s", selection.Coverage, strings.Join(CoverageLevels, ", "))
+		}
+	}
+
+	if cldr.FS == nil {
+		return nil, ErrNoFS
+	}
+
+	if minLevel != -1 && cldr.CoverageLevels == nil {
+		levels, err := LoadCoverageLevels(cldr.FS, "properties/coverageLevels.txt")
+		if err != nil {
+			return nil, err
+		}
+
+		cldr.CoverageLevels = levels
+	}
+
+	available, err := cldr.GetAvailableLocales()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, code := range selection.Locales {
+		if !slices.Contains(available, code) {
+			return nil, fmt.Errorf("unknown locale %q in %s/main", code, cldr.Path)
+		}
+	}
+
+	codes := []string{}
+	for _, code := range available {
+		selected := selection.All || slices.Contains(selection.Locales, code)
+
+		for _, glob := range selection.Globs {
+			matched, err := filepath.Match(glob, code)
+			if err != nil {
+				return nil, fmt.Errorf("invalid glob %q: %w", glob, err)
+			}
+
+			selected = selected || matched
+		}
+
+		if !selected {
+			continue
+		}
+
+		if minLevel != -1 && slices.Index(CoverageLevels, cldr.GetCoverageLevel(code)) < minLevel {
+			continue
+		}
+
+		codes = append(codes, code)
+	}
+
+	sort.Strings(codes)
+
+	return codes, nil
+}
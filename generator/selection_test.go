@@ -0,0 +1,79 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getSelectionCLDR(t *testing.T) *CLDR {
+	path := t.TempDir()
+
+	assert.Nil(t, os.MkdirAll(path+"/main", 0755))
+	assert.Nil(t, os.MkdirAll(path+"/properties", 0755))
+
+	for _, code := range []string{"root", "de", "de_CH", "fr", "fr_CA", "hi", "sr", "sr_Latn"} {
+		assert.Nil(t, os.WriteFile(path+"/main/"+code+".xml", []byte("<ldml/>"), 0644))
+	}
+
+	levels := "# coverage\nde ;\tmodern ;\tGerman\nfr ;\tmodern ;\tFrench\nhi ;\tmoderate ;\tHindi\nsr_Latn ;\tbasic ;\tSerbian (Latin)\n"
+	assert.Nil(t, os.WriteFile(path+"/properties/coverageLevels.txt", []byte(levels), 0644))
+
+	return &CLDR{Path: path, FS: os.DirFS(path)}
+}
+
+func Test_Select_Locales(t *testing.T) {
+	cldr := getSelectionCLDR(t)
+
+	codes, err := SelectLocales(cldr, &Selection{Locales: []string{"fr", "de_CH"}, Globs: []string{"sr*"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"de_CH", "fr", "sr", "sr_Latn"}, codes)
+
+	codes, err = SelectLocales(cldr, &Selection{All: true})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"de", "de_CH", "fr", "fr_CA", "hi", "sr", "sr_Latn"}, codes)
+
+	_, err = SelectLocales(cldr, &Selection{Locales: []string{"ja"}})
+	assert.NotNil(t, err)
+
+	_, err = SelectLocales(cldr, &Selection{Globs: []string{"fr["}})
+	assert.NotNil(t, err)
+}
+
+func Test_Select_Locales_Coverage(t *testing.T) {
+	cldr := getSelectionCLDR(t)
+
+	codes, err := SelectLocales(cldr, &Selection{All: true, Coverage: "modern"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"de", "de_CH", "fr", "fr_CA"}, codes)
+
+	codes, err = SelectLocales(cldr, &Selection{All: true, Coverage: "moderate"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"de", "de_CH", "fr", "fr_CA", "hi"}, codes)
+
+	codes, err = SelectLocales(cldr, &Selection{Globs: []string{"sr*"}, Coverage: "basic"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sr_Latn"}, codes)
+
+	_, err = SelectLocales(cldr, &Selection{All: true, Coverage: "great"})
+	assert.NotNil(t, err)
+}
+
+func Test_Select_Locales_No_FS(t *testing.T) {
+	cldr := &CLDR{}
+
+	_, err := cldr.GetAvailableLocales()
+	assert.ErrorIs(t, err, ErrNoFS)
+
+	_, err = cldr.HasLocale("fr")
+	assert.ErrorIs(t, err, ErrNoFS)
+
+	_, err = SelectLocales(cldr, &Selection{All: true})
+	assert.ErrorIs(t, err, ErrNoFS)
+}
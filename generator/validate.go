@@ -0,0 +1,279 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/rande/golocales/dto"
+)
+
+// The severities of the issues: an error makes the runtime panic or return
+// an empty value, a warning is a missing value replaced by a fallback.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue is a value missing in a locale and in its parents.
+type Issue struct {
+	Severity string `json:"severity"`
+	// symbol, number_pattern, calendar_label or pattern
+	Check string `json:"check"`
+	// the name of the value, ie: latn.default_standard or m_format_wide
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+// LocaleReport is the result of the validation of a locale, the values are
+// the ones defined by the locale, not the inherited ones.
+type LocaleReport struct {
+	Locale string `json:"locale"`
+	Parent string `json:"parent,omitempty"`
+	// the CLDR coverage level, only known when the locales are selected by
+	// coverage level
+	Coverage string         `json:"coverage,omitempty"`
+	Values   map[string]int `json:"values"`
+	Issues   []*Issue       `json:"issues"`
+}
+
+// Report is the validation of the generated locales.
+type Report struct {
+	CldrVersion string          `json:"cldr_version"`
+	Errors      int             `json:"errors"`
+	Warnings    int             `json:"warnings"`
+	Locales     []*LocaleReport `json:"locales"`
+}
+
+// ValidatedCalendar is the calendar of the date and time styles.
+const ValidatedCalendar = "gregorian"
+
+// requiredSymbols are the symbols used to format the numbers.
+var requiredSymbols = []string{"Decimal", "Group", "PercentSign", "PlusSign", "MinusSign"}
+
+// requiredLabels are the calendar labels and their number, the stand-alone
+// labels fall back to the format ones at runtime.
+var requiredLabels = []struct {
+	Key      string
+	Count    int
+	Severity string
+}{
+	{"m_format_abbreviated", 12, SeverityError},
+	{"m_format_wide", 12, SeverityError},
+	{"m_format_narrow", 12, SeverityError},
+	{"m_stand-alone_abbreviated", 12, SeverityWarning},
+	{"m_stand-alone_wide", 12, SeverityWarning},
+	{"m_stand-alone_narrow", 12, SeverityWarning},
+	{"d_format_abbreviated", 7, SeverityError},
+	{"d_format_wide", 7, SeverityError},
+	{"d_format_narrow", 7, SeverityError},
+	{"d_stand-alone_abbreviated", 7, SeverityWarning},
+	{"d_stand-alone_wide", 7, SeverityWarning},
+	{"d_stand-alone_narrow", 7, SeverityWarning},
+}
+
+// requiredPatterns are the patterns of the date and time styles.
+var requiredPatterns = []string{
+	"date_full", "date_long", "date_medium", "date_short",
+	"time_full", "time_long", "time_medium", "time_short",
+}
+
+// ValidateCLDR validates the loaded locales, root included, sorted by code.
+func ValidateCLDR(cldr *CLDR) (*Report, error) {
+	report := &Report{CldrVersion: cldr.Version, Locales: []*LocaleReport{}}
+
+	codes := []string{}
+	for code := range cldr.Locales {
+		codes = append(codes, code)
+	}
+
+	sort.Strings(codes)
+
+	locales := []*Locale{}
+	if cldr.RootLocale != nil {
+		locales = append(locales, cldr.RootLocale)
+	}
+
+	for _, code := range codes {
+		locales = append(locales, cldr.Locales[code])
+	}
+
+	// the parents are converted once
+	converted := map[*Locale]*dto.Locale{}
+
+	for _, locale := range locales {
+		r, err := validateLocale(locale, converted)
+		if err != nil {
+			return nil, err
+		}
+
+		if !locale.IsRoot {
+			r.Coverage = cldr.GetCoverageLevel(locale.Code)
+		}
+
+		for _, issue := range r.Issues {
+			if issue.Severity == SeverityError {
+				report.Errors++
+			} else {
+				report.Warnings++
+			}
+		}
+
+		report.Locales = append(report.Locales, r)
+	}
+
+	return report, nil
+}
+
+// ValidateLocale checks that the values used by the formatters are defined
+// by the locale or by one of its parents: the symbols and the patterns of
+// the default numbering system, the labels and the patterns of the
+// gregorian calendar. The values are read with the getters of the runtime
+// locale, see GetDtoLocale.
+func ValidateLocale(locale *Locale) (*LocaleReport, error) {
+	return validateLocale(locale, map[*Locale]*dto.Locale{})
+}
+
+func validateLocale(locale *Locale, converted map[*Locale]*dto.Locale) (*LocaleReport, error) {
+	r := &LocaleReport{
+		Locale: locale.Code,
+		Values: map[string]int{
+			"territories": len(locale.Territories),
+			"currencies":  len(locale.Currencies),
+			"calendars":   len(locale.Calendars),
+			"fields":      len(locale.Fields),
+			"time_zones":  len(locale.ZoneNames) + len(locale.MetaZoneNames),
+		},
+		Issues: []*Issue{},
+	}
+
+	if locale.Parent != nil {
+		r.Parent = locale.Parent.Code
+	}
+
+	add := func(severity, check, key, message string, args ...interface{}) {
+		r.Issues = append(r.Issues, &Issue{Severity: severity, Check: check, Key: key, Message: fmt.Sprintf(message, args...)})
+	}
+
+	system := locale.Number.DefaultNumberSystem
+	if system == "" {
+		add(SeverityError, "symbol", "default_number_system", "no default numbering system")
+		return r, nil
+	}
+
+	l, err := getDtoLocaleChain(locale, converted)
+	if err != nil {
+		return nil, err
+	}
+
+	if symbol := l.GetSymbol(system); symbol == nil {
+		add(SeverityError, "symbol", system, "no symbols for the numbering system %s", system)
+	} else {
+		for _, name := range getEmptyFields(symbol, requiredSymbols) {
+			add(SeverityError, "symbol", system+"."+name, "the symbol %s is empty", name)
+		}
+	}
+
+	for _, p := range []struct {
+		kind     string
+		name     string
+		formats  func(system, name string) []*dto.NumberFormat
+		severity string
+	}{
+		{"decimal", "default", l.GetDecimalFormats, SeverityError},
+		{"percent", "default", l.GetPercentFormats, SeverityError},
+		{"currency", "default_standard", l.GetCurrencyFormats, SeverityError},
+		// the standard currency format is used if missing
+		{"currency", "default_accounting", l.GetCurrencyFormats, SeverityWarning},
+	} {
+		if len(p.formats(system, p.name)) == 0 {
+			add(p.severity, "number_pattern", p.kind+"."+system+"."+p.name, "no %s pattern %s for the numbering system %s", p.kind, p.name, system)
+		}
+	}
+
+	for _, label := range requiredLabels {
+		count := 0
+		for _, value := range l.GetCalendarLabels(ValidatedCalendar, label.Key) {
+			if value != "" {
+				count++
+			}
+		}
+
+		if count != label.Count {
+			add(label.Severity, "calendar_label", ValidatedCalendar+"."+label.Key, "%d labels, %d expected", count, label.Count)
+		}
+	}
+
+	for _, name := range requiredPatterns {
+		if l.GetCalendarPattern(ValidatedCalendar, name) == "" {
+			add(SeverityError, "pattern", ValidatedCalendar+"."+name, "no pattern")
+		}
+	}
+
+	return r, nil
+}
+
+// getDtoLocaleChain converts the locale and its parents to the runtime
+// structs, the converted locales are reused.
+func getDtoLocaleChain(locale *Locale, converted map[*Locale]*dto.Locale) (*dto.Locale, error) {
+	if l, ok := converted[locale]; ok {
+		return l, nil
+	}
+
+	l, err := GetDtoLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	if locale.Parent != nil {
+		if l.Parent, err = getDtoLocaleChain(locale.Parent, converted); err != nil {
+			return nil, err
+		}
+	}
+
+	converted[locale] = l
+
+	return l, nil
+}
+
+// getEmptyFields returns the names of the empty string fields.
+func getEmptyFields(symbol *dto.Symbol, names []string) []string {
+	v := reflect.ValueOf(*symbol)
+
+	empty := []string{}
+	for _, name := range names {
+		if v.FieldByName(name).String() == "" {
+			empty = append(empty, name)
+		}
+	}
+
+	return empty
+}
+
+// WriteReport writes the report as JSON.
+func WriteReport(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
+
+// WriteReportSummary writes the number of issues and the errors.
+func WriteReportSummary(w io.Writer, report *Report) {
+	fmt.Fprintf(w, "Validated %d locales: %d errors, %d warnings\n", len(report.Locales), report.Errors, report.Warnings)
+
+	for _, r := range report.Locales {
+		for _, issue := range r.Issues {
+			if issue.Severity == SeverityError {
+				fmt.Fprintf(w, "  [%s] %s %s: %s\n", r.Locale, issue.Check, issue.Key, issue.Message)
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getValidRootLocale() *Locale {
+	months := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}
+	days := []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+	calendar := &Calendar{
+		System:   "gregorian",
+		Labels:   map[string][]string{},
+		Patterns: map[string]string{},
+	}
+
+	for _, l := range requiredLabels {
+		if l.Count == 12 {
+			calendar.Labels[l.Key] = months
+		} else {
+			calendar.Labels[l.Key] = days
+		}
+	}
+
+	for _, name := range requiredPatterns {
+		calendar.Patterns[name] = "y"
+	}
+
+	return &Locale{
+		IsRoot: true,
+		Code:   "root",
+		Number: &Number{
+			DefaultNumberSystem: "latn",
+			Symbols: map[string]*Symbol{
+				"latn": {System: "latn", Decimal: ".", Group: ",", PercentSign: "%", PlusSign: "+", MinusSign: "-"},
+			},
+			Decimals:   map[string]FormatGroup{"latn": {"default": {{Pattern: "#,##0.###"}}}},
+			Percents:   map[string]FormatGroup{"latn": {"default": {{Pattern: "#,##0%"}}}},
+			Currencies: map[string]FormatGroup{"latn": {"default_standard": {{Pattern: "¤#,##0.00"}}, "default_accounting": {{Pattern: "¤#,##0.00"}}}},
+		},
+		Calendars: map[string]*Calendar{"gregorian": calendar},
+	}
+}
+
+func Test_Validate_Locale(t *testing.T) {
+	root := getValidRootLocale()
+
+	r, err := ValidateLocale(root)
+	assert.NoError(t, err)
+	assert.Equal(t, "root", r.Locale)
+	assert.Empty(t, r.Issues)
+
+	// the values are inherited from the parent
+	fr := &Locale{
+		Code:   "fr",
+		Parent: root,
+		Number: &Number{
+			DefaultNumberSystem: "latn",
+			Symbols:             map[string]*Symbol{},
+			Decimals:            map[string]FormatGroup{},
+			Percents:            map[string]FormatGroup{},
+			Currencies:          map[string]FormatGroup{},
+		},
+		Calendars: map[string]*Calendar{
+			"gregorian": {System: "gregorian", Labels: map[string][]string{"m_format_wide": {"janvier"}}},
+		},
+	}
+
+	r, err = ValidateLocale(fr)
+	assert.NoError(t, err)
+	assert.Equal(t, "root", r.Parent)
+	assert.Equal(t, []*Issue{
+		{Severity: SeverityError, Check: "calendar_label", Key: "gregorian.m_format_wide", Message: "1 labels, 12 expected"},
+	}, r.Issues)
+
+	// the missing values in the parent chain
+	delete(root.Number.Currencies["latn"], "default_accounting")
+	delete(root.Number.Percents["latn"], "default")
+	delete(root.Calendars["gregorian"].Patterns, "time_short")
+	root.Number.Symbols["latn"].MinusSign = ""
+	fr.Calendars["gregorian"].Labels = map[string][]string{}
+
+	r, err = ValidateLocale(fr)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Issue{
+		{Severity: SeverityError, Check: "symbol", Key: "latn.MinusSign", Message: "the symbol MinusSign is empty"},
+		{Severity: SeverityError, Check: "number_pattern", Key: "percent.latn.default", Message: "no percent pattern default for the numbering system latn"},
+		{Severity: SeverityWarning, Check: "number_pattern", Key: "currency.latn.default_accounting", Message: "no currency pattern default_accounting for the numbering system latn"},
+		{Severity: SeverityError, Check: "pattern", Key: "gregorian.time_short", Message: "no pattern"},
+	}, r.Issues)
+
+	fr.Number.DefaultNumberSystem = ""
+
+	r, err = ValidateLocale(fr)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Issue{
+		{Severity: SeverityError, Check: "symbol", Key: "default_number_system", Message: "no default numbering system"},
+	}, r.Issues)
+}
+
+func Test_Validate_CLDR(t *testing.T) {
+	root := getValidRootLocale()
+	fr := &Locale{
+		Code:   "fr",
+		Parent: root,
+		Number: &Number{DefaultNumberSystem: "arab"},
+	}
+
+	cldr := &CLDR{
+		Version:        "44",
+		RootLocale:     root,
+		Locales:        map[string]*Locale{"fr": fr},
+		CoverageLevels: map[string]string{"fr": "modern"},
+	}
+
+	report, err := ValidateCLDR(cldr)
+	assert.NoError(t, err)
+	assert.Equal(t, "44", report.CldrVersion)
+	assert.Equal(t, 4, report.Errors)
+	assert.Equal(t, 1, report.Warnings)
+	assert.Len(t, report.Locales, 2)
+	assert.Equal(t, "root", report.Locales[0].Locale)
+	assert.Equal(t, "", report.Locales[0].Coverage)
+	assert.Equal(t, "modern", report.Locales[1].Coverage)
+
+	buffer := bytes.NewBuffer([]byte{})
+	assert.Nil(t, WriteReport(buffer, report))
+
+	decoded := &Report{}
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), decoded))
+	assert.Equal(t, report, decoded)
+
+	buffer.Reset()
+	WriteReportSummary(buffer, report)
+	assert.Equal(t, "Validated 2 locales: 4 errors, 1 warnings\n"+
+		"  [fr] symbol arab: no symbols for the numbering system arab\n"+
+		"  [fr] number_pattern decimal.arab.default: no decimal pattern default for the numbering system arab\n"+
+		"  [fr] number_pattern percent.arab.default: no percent pattern default for the numbering system arab\n"+
+		"  [fr] number_pattern currency.arab.default_standard: no currency pattern default_standard for the numbering system arab\n", buffer.String())
+}
@@ -1,4 +1,4 @@
-package main
+package generator
 
 import (
 	"bytes"
@@ -15,7 +15,8 @@ func Test_Write_Ldml(t *testing.T) {
 
 	assert.Nil(t, err)
 
-	locale := LoadLocale(GetCLDR(), ldml)
+	locale, err := LoadLocale(GetCLDR(t), ldml)
+	assert.NoError(t, err)
 
 	buffer := bytes.NewBuffer([]byte{})
 
@@ -30,3 +31,84 @@ func Test_Write_Ldml(t *testing.T) {
 
 	fmt.Printf("%s\n", buffer.String())
 }
+
+func Test_Get_Dto_Locale(t *testing.T) {
+	locale := &Locale{
+		Code:     "fr_CA",
+		Language: "fr",
+		Parent:   &Locale{Code: "fr"},
+		Territories: map[string]*Territory{
+			"FR": {Code: "FR", Name: "France", Numeric: "250"},
+		},
+		Currencies: map[string]*Currency{
+			"USD": {Code: "USD", Name: "dollar des États-Unis", Symbol: "$ US", Digits: "2", Alts: map[string]string{"narrow": "$"}},
+		},
+		Number: &Number{
+			Symbols: map[string]*Symbol{"latn": {System: "latn", Decimal: ","}},
+			Percents: map[string]FormatGroup{
+				"latn": {"standard": {{Type: "standard", Pattern: "#,##0 %", PrimaryGroupingSize: 3, StandardPattern: "0 %"}}},
+			},
+			DefaultNumberSystem: "latn",
+		},
+		Calendars: map[string]*Calendar{
+			"gregorian": {
+				System:     "gregorian",
+				Patterns:   map[string]string{"date_short": "y-MM-dd"},
+				DayPeriods: map[string][]*DayPeriod{"p_format_wide_a": {{Label: "a.m.", At: -1, From: 0, Before: 1200}}},
+			},
+		},
+		PluralRules: []*PluralRule{{Count: "one", Rule: "i = 0,1"}},
+	}
+
+	l, err := GetDtoLocale(locale)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "fr_CA", l.Name)
+	assert.Equal(t, "France", l.Territories["FR"].Name)
+	assert.Empty(t, l.Territories["FR"].Numeric)
+	assert.Equal(t, "$ US", l.Currencies["USD"].Symbol)
+	assert.Equal(t, uint8(0), l.Currencies["USD"].Digits)
+	assert.Equal(t, "$", l.Currencies["USD"].Alts["narrow"])
+	assert.Equal(t, ",", l.Number.Symbols["latn"].Decimal)
+	assert.Empty(t, l.Number.Percents["latn"]["standard"][0].Type)
+	assert.Equal(t, "0 %", l.Number.Percents["latn"]["standard"][0].StandardPattern)
+	assert.Nil(t, l.Number.Decimals)
+	assert.Equal(t, "y-MM-dd", l.Calendars["gregorian"].Patterns["date_short"])
+	assert.Equal(t, "a.m.", l.Calendars["gregorian"].DayPeriods["p_format_wide_a"][0].Label)
+	assert.Equal(t, "i = 0,1", l.PluralRules["one"])
+
+	buffer := bytes.NewBuffer([]byte{})
+	assert.Nil(t, WriteLocaleJson(locale, buffer))
+	assert.Contains(t, buffer.String(), `"Parent":"fr"`)
+}
+
+func Test_Get_Dto_Locale_Invalid_Digits(t *testing.T) {
+	locale := &Locale{
+		IsRoot: true,
+		Code:   "root",
+		Currencies: map[string]*Currency{
+			"USD": {Code: "USD", Digits: "two", Rounding: "0", CashDigits: "0", CashRounding: "0"},
+		},
+		Number: &Number{},
+	}
+
+	_, err := GetDtoLocale(locale)
+	assert.ErrorContains(t, err, "invalid digits of the currency USD")
+	assert.Error(t, WriteLocaleJson(locale, bytes.NewBuffer([]byte{})))
+}
+
+func Test_Format_Code(t *testing.T) {
+	src := "package fr\n\nimport (\n    \"time\"\n\n    . \"github.com/rande/golocales/dto\"\n)\n\nvar f = func(tm time.Time) string {\nreturn \"\"\n}\n\nvar l = &Locale{}\n"
+
+	code, err := FormatCode("fr.go", []byte(src))
+	assert.Nil(t, err)
+	assert.Equal(t, "package fr\n\nimport (\n\t\"time\"\n\n\t. \"github.com/rande/golocales/dto\"\n)\n\nvar f = func(tm time.Time) string {\n\treturn \"\"\n}\n\nvar l = &Locale{}\n", string(code))
+
+	_, err = FormatCode("fr.go", []byte("package fr\n\nvar l = {"))
+	assert.EqualError(t, err, "unable to format the generated file fr.go: 3:9: expected operand, found '{'")
+}
+
+func Test_Write_Go_Missing_Template(t *testing.T) {
+	err := WriteGo("missing", t.TempDir(), &CLDR{})
+	assert.ErrorContains(t, err, "invalid template missing: ")
+}
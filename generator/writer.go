@@ -1,88 +1,133 @@
-package main
+package generator
 
 import (
+	"bytes"
+	"fmt"
+	"go/format"
 	"io"
-	"log"
 	"os"
-	"os/exec"
 	"text/template"
 )
 
-func FormatCode(path string) {
-	cmd := exec.Command("goimports", "-w", path)
-	if err := cmd.Run(); err != nil {
-		log.Panic("failed execute \"goimports\" for file ", path, ": ", err)
+// FormatCode formats the generated code with go/format, the templates
+// only import the packages they use.
+func FormatCode(filename string, src []byte) ([]byte, error) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("unable to format the generated file %s: %w", filename, err)
 	}
 
-	cmd = exec.Command("gofmt", "-s", "-w", path)
-	if err := cmd.Run(); err != nil {
-		log.Panic("failed execute \"gofmt\" for file ", path, ": ", err)
+	return formatted, nil
+}
+
+// writeCode formats the generated code and writes the file, nothing is
+// written if the code is invalid.
+func writeCode(filename string, src []byte) error {
+	code, err := FormatCode(filename, src)
+	if err != nil {
+		return err
 	}
+
+	return os.WriteFile(filename, code, 0644)
 }
 
-func WriteLocale(localePath string, locale *Locale) {
+func WriteLocale(localePath string, locale *Locale) error {
 	path := localePath + "/" + locale.Code
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		if err := os.Mkdir(path, 0755); err != nil {
-			log.Panic(err)
-		}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
 	}
 
-	localeFilepath := path + "/" + locale.Code + ".go"
+	buffer := bytes.NewBuffer([]byte{})
+	if err := WriteLocaleGo(locale, buffer); err != nil {
+		return err
+	}
 
-	if f, err := os.Create(localeFilepath); err != nil {
-		log.Panic(err)
-	} else {
-		defer f.Close()
+	return writeCode(path+"/"+locale.Code+".go", buffer.Bytes())
+}
 
-		if err := WriteLocaleGo(locale, f); err != nil {
-			log.Panic(err)
-		}
+func WriteLocaleGo(locale *Locale, w io.Writer) error {
+	tpl, err := parseTemplate("locale")
+	if err != nil {
+		return err
 	}
 
-	FormatCode(localeFilepath)
+	return tpl.Execute(w, getLocaleContext(locale))
 }
 
-func WriteLocaleGo(locale *Locale, w io.Writer) error {
-	fs := GetEmbedFS()
+// parseTemplate parses the embedded template, ie: locale for
+// templates/locale.tmpl
+func parseTemplate(name string) (*template.Template, error) {
+	tpl, err := template.ParseFS(GetEmbedFS(), "templates/"+name+".tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
 
-	tpl := template.Must(template.ParseFS(fs, "templates/locale.tmpl"))
+	return tpl, nil
+}
 
-	ctx := map[string]interface{}{
+// getLocaleContext returns the context of the locale templates.
+func getLocaleContext(locale *Locale) map[string]interface{} {
+	return map[string]interface{}{
 		"Locale":      locale,
 		"Code":        locale.Code,
 		"Territories": locale.Territories,
 		"Currencies":  locale.Currencies,
 		"TimeZones":   locale.TimeZones,
 	}
-
-	return tpl.Execute(w, ctx)
 }
 
-func WriteGo(filename, basePath string, cldr *CLDR) error {
-	fs := GetEmbedFS()
+// WriteLocaleTemplate writes the file of a plugin template in the package
+// of the locale.
+func WriteLocaleTemplate(localePath string, locale *Locale, t *Template) error {
+	return writeTemplate(localePath+"/"+locale.Code+"/"+t.Name+".go", t, getLocaleContext(locale))
+}
 
-	var f *os.File
-	var err error
+// WriteTemplate writes the file of a plugin template in the locales folder.
+func WriteTemplate(localePath string, cldr *CLDR, t *Template) error {
+	return writeTemplate(localePath+"/"+t.Name+".go", t, map[string]interface{}{"Cldr": cldr})
+}
 
-	basePath = basePath + "/" + filename + ".go"
+func writeTemplate(filename string, t *Template, ctx map[string]interface{}) error {
+	tpl, err := template.New(t.Name).Parse(t.Content)
+	if err != nil {
+		return err
+	}
 
-	if f, err = os.Create(basePath); err != nil {
-		log.Panic(err)
+	buffer := bytes.NewBuffer([]byte{})
+	if err := tpl.Execute(buffer, ctx); err != nil {
+		return err
 	}
 
-	defer f.Close()
+	return writeCode(filename, buffer.Bytes())
+}
 
-	tpl := template.Must(template.ParseFS(fs, "templates/"+filename+".tmpl"))
+func WriteGo(filename, basePath string, cldr *CLDR) error {
+	tpl, err := parseTemplate(filename)
+	if err != nil {
+		return err
+	}
 
 	ctx := map[string]interface{}{
 		"Cldr": cldr,
 	}
 
-	tpl.Execute(f, ctx)
+	buffer := bytes.NewBuffer([]byte{})
+	if err := tpl.Execute(buffer, ctx); err != nil {
+		return err
+	}
+
+	return writeCode(basePath+"/"+filename+".go", buffer.Bytes())
+}
 
-	FormatCode(basePath)
+// WriteAll generates the package importing all the locales, so the
+// registry contains every locale.
+func WriteAll(localePath string, cldr *CLDR) error {
+	path := localePath + "/all"
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
 
-	return nil
+	return WriteGo("all", path, cldr)
 }
@@ -0,0 +1,236 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/rande/golocales/dto"
+)
+
+// The output formats of the locales: a Go package per locale, or a data
+// file per locale loaded at runtime by golocales.DataLoader. The root
+// locale is always a Go package.
+const (
+	FormatGo   = "go"
+	FormatData = "data"
+)
+
+var OutputFormats = []string{FormatGo, FormatData}
+
+// WriteLocaleData writes the data file of the locale in the data folder.
+func WriteLocaleData(localePath string, locale *Locale) error {
+	path := localePath + "/data"
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path + "/" + locale.Code + ".json")
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	return WriteLocaleJson(locale, f)
+}
+
+func WriteLocaleJson(locale *Locale, w io.Writer) error {
+	l, err := GetDtoLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	data := &dto.LocaleData{
+		Locale: l,
+	}
+
+	if locale.Parent != nil {
+		data.Parent = locale.Parent.Code
+	}
+
+	return json.NewEncoder(w).Encode(data)
+}
+
+// WriteData generates the package embedding the data files and registering
+// the locales.
+func WriteData(localePath string, cldr *CLDR) error {
+	return WriteGo("data", localePath+"/data", cldr)
+}
+
+// GetDtoLocale converts the locale to the runtime struct, the values are the
+// ones written by the locale.tmpl template.
+func GetDtoLocale(locale *Locale) (*dto.Locale, error) {
+	l := &dto.Locale{
+		Name:          locale.Code,
+		Language:      locale.Language,
+		Script:        locale.Script,
+		Territory:     locale.Territory,
+		Variant:       locale.Variant,
+		Territories:   map[string]*dto.Territory{},
+		Currencies:    map[string]*dto.Currency{},
+		TimeZones:     map[string]dto.TimeZone{},
+		MetaZoneNames: locale.MetaZoneNames,
+		ZoneNames:     locale.ZoneNames,
+		Calendars:     map[string]*dto.Calendar{},
+		Fields:        map[string]*dto.Field{},
+		PluralRules:   map[string]string{},
+	}
+
+	for _, t := range locale.Territories {
+		if locale.IsRoot {
+			l.Territories[t.Code] = &dto.Territory{Numeric: t.Numeric, Alpha3: t.Alpha3}
+		} else {
+			l.Territories[t.Code] = &dto.Territory{Name: t.Name, Alts: getAlts(t.Alts)}
+		}
+	}
+
+	for _, c := range locale.Currencies {
+		if locale.IsRoot {
+			currency := &dto.Currency{Symbol: c.Symbol, Numeric: c.Numeric, Alts: getAlts(c.Alts)}
+
+			for _, v := range []struct {
+				value string
+				field *uint8
+			}{
+				{c.Digits, &currency.Digits},
+				{c.Rounding, &currency.Rounding},
+				{c.CashDigits, &currency.CashDigits},
+				{c.CashRounding, &currency.CashRounding},
+			} {
+				n, err := strconv.ParseUint(v.value, 10, 8)
+				if err != nil {
+					return nil, fmt.Errorf("invalid digits of the currency %s: %w", c.Code, err)
+				}
+
+				*v.field = uint8(n)
+			}
+
+			l.Currencies[c.Code] = currency
+		} else {
+			l.Currencies[c.Code] = &dto.Currency{Symbol: c.Symbol, Name: c.Name, Alts: getAlts(c.Alts)}
+		}
+	}
+
+	for _, z := range locale.TimeZones {
+		l.TimeZones[z.Code] = dto.TimeZone(z.Name)
+	}
+
+	if locale.TimeFormat != nil {
+		l.TimeFormat = &dto.TimeFormat{
+			Hour:           locale.TimeFormat.Hour,
+			Gmt:            locale.TimeFormat.Gmt,
+			GmtZero:        locale.TimeFormat.GmtZero,
+			Region:         locale.TimeFormat.Region,
+			RegionDaylight: locale.TimeFormat.RegionDaylight,
+			RegionStandard: locale.TimeFormat.RegionStandard,
+		}
+	}
+
+	l.Number = &dto.Number{
+		Symbols:               map[string]*dto.Symbol{},
+		MinimumGroupingDigits: locale.Number.MinimumGroupingDigits,
+		DefaultNumberSystem:   locale.Number.DefaultNumberSystem,
+		Decimals:              getDtoFormatGroups(locale.Number.Decimals, false),
+		Percents:              getDtoFormatGroups(locale.Number.Percents, true),
+		Currencies:            getDtoFormatGroups(locale.Number.Currencies, false),
+	}
+
+	for _, s := range locale.Number.Symbols {
+		l.Number.Symbols[s.System] = &dto.Symbol{
+			System:                 s.System,
+			MinusSign:              s.MinusSign,
+			PlusSign:               s.PlusSign,
+			Exponential:            s.Exponential,
+			SuperscriptingExponent: s.SuperscriptingExponent,
+			Decimal:                s.Decimal,
+			Group:                  s.Group,
+			CurrencyGroup:          s.CurrencyGroup,
+			PercentSign:            s.PercentSign,
+			PerMilleSign:           s.PerMilleSign,
+			ApproximatelySign:      s.ApproximatelySign,
+			Infinity:               s.Infinity,
+			TimeSeparator:          s.TimeSeparator,
+		}
+	}
+
+	for _, c := range locale.Calendars {
+		calendar := &dto.Calendar{
+			System:     c.System,
+			Labels:     c.Labels,
+			Patterns:   c.Patterns,
+			Skeletons:  c.Skeletons,
+			Intervals:  c.Intervals,
+			DayPeriods: map[string][]*dto.DayPeriod{},
+		}
+
+		for key, periods := range c.DayPeriods {
+			calendar.DayPeriods[key] = []*dto.DayPeriod{}
+			for _, p := range periods {
+				calendar.DayPeriods[key] = append(calendar.DayPeriods[key], &dto.DayPeriod{Label: p.Label, At: p.At, From: p.From, Before: p.Before})
+			}
+		}
+
+		l.Calendars[c.System] = calendar
+	}
+
+	for key, f := range locale.Fields {
+		l.Fields[key] = &dto.Field{DisplayName: f.DisplayName, Relative: f.Relative, Future: f.Future, Past: f.Past}
+	}
+
+	for _, r := range locale.PluralRules {
+		l.PluralRules[r.Count] = r.Rule
+	}
+
+	return l, nil
+}
+
+// getDtoFormatGroups converts the number formats, only the grouping sizes
+// and the standard pattern are used by the percents.
+func getDtoFormatGroups(groups map[string]FormatGroup, percent bool) map[string]dto.FormatGroup {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	systems := map[string]dto.FormatGroup{}
+	for system, group := range groups {
+		systems[system] = dto.FormatGroup{}
+
+		for name, formats := range group {
+			list := []*dto.NumberFormat{}
+			for _, f := range formats {
+				format := &dto.NumberFormat{
+					PrimaryGroupingSize:   f.PrimaryGroupingSize,
+					SecondaryGroupingSize: f.SecondaryGroupingSize,
+					StandardPattern:       f.StandardPattern,
+				}
+
+				if !percent {
+					format.Type, format.Alt, format.Count = f.Type, f.Alt, f.Count
+				}
+
+				list = append(list, format)
+			}
+
+			systems[system][name] = list
+		}
+	}
+
+	return systems
+}
+
+func getAlts(alts map[string]string) map[string]string {
+	if len(alts) == 0 {
+		return nil
+	}
+
+	return alts
+}
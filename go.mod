@@ -2,10 +2,12 @@ module github.com/rande/golocales
 
 go 1.21.3
 
-require github.com/stretchr/testify v1.8.4
+require (
+	github.com/cockroachdb/apd/v3 v3.2.1
+	github.com/stretchr/testify v1.8.4
+)
 
 require (
-	github.com/cockroachdb/apd/v3 v3.2.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
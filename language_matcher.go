@@ -0,0 +1,383 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rande/golocales/dto"
+)
+
+// Confidence is the level of confidence of a locale match.
+type Confidence int
+
+const (
+	// NoConfidence means the locales do not match.
+	NoConfidence Confidence = iota
+	// LowConfidence means the locales share the language, but not the
+	// script, ie: zh_Hant and zh_Hans.
+	LowConfidence
+	// HighConfidence means the locales are close, ie: pt_BR and pt_PT.
+	HighConfidence
+	// ExactConfidence means the locales are the same once maximized: fr
+	// and fr_FR.
+	ExactConfidence
+)
+
+const (
+	// MatchThreshold is the distance from which two locales do not match.
+	MatchThreshold = 50
+	// the distance added per requested locale, so a close match of a
+	// requested locale is better than an exact match of the next one
+	matchDemotion = 5
+)
+
+// LocaleMatch is a supported locale matching a requested one.
+type LocaleMatch struct {
+	Requested  string
+	Supported  string
+	Distance   int
+	Confidence Confidence
+}
+
+// languageTag is a tag split into its language, script and region.
+type languageTag struct {
+	Language string
+	Script   string
+	Region   string
+}
+
+func parseLanguageTag(tag string) languageTag {
+	parts := strings.Split(NormalizeTag(tag), "_")
+
+	t := languageTag{Language: parts[0]}
+	if t.Language == "root" {
+		t.Language = "und"
+	}
+
+	for _, part := range parts[1:] {
+		switch {
+		case len(part) == 4 && t.Script == "" && t.Region == "":
+			t.Script = part
+		case isTerritoryCode(part) && t.Region == "":
+			t.Region = part
+		}
+	}
+
+	return t
+}
+
+func (t languageTag) String() string {
+	parts := []string{t.Language}
+	for _, part := range []string{t.Script, t.Region} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, "_")
+}
+
+// maximize fills the missing script and region with the likely subtags.
+// http://www.unicode.org/reports/tr35/#Likely_Subtags
+func (t languageTag) maximize() languageTag {
+	supplemental := dto.GetSupplemental()
+
+	candidates := []languageTag{
+		{t.Language, t.Script, t.Region},
+		{t.Language, "", t.Region},
+		{t.Language, t.Script, ""},
+		{t.Language, "", ""},
+	}
+
+	if t.Language != "und" {
+		candidates = append(candidates, languageTag{"und", t.Script, ""})
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Language == "und" && candidate.Script == "" && candidate.Region == "" && t.Language != "und" {
+			continue
+		}
+
+		likely := supplemental.GetLikelySubtags(candidate.String())
+		if likely == "" {
+			continue
+		}
+
+		found := parseLanguageTag(likely)
+		if t.Language != "und" {
+			found.Language = t.Language
+		}
+
+		if t.Script != "" {
+			found.Script = t.Script
+		}
+
+		if t.Region != "" {
+			found.Region = t.Region
+		}
+
+		return found
+	}
+
+	return t
+}
+
+// AddLikelySubtags returns the tag with its most likely script and region:
+// "fr" is fr_Latn_FR and "zh-TW" is zh_Hant_TW.
+func AddLikelySubtags(tag string) string {
+	return parseLanguageTag(tag).maximize().String()
+}
+
+// RemoveLikelySubtags returns the shortest tag having the same likely
+// subtags: "fr_Latn_FR" is fr and "zh_Hant_TW" is zh_TW.
+func RemoveLikelySubtags(tag string) string {
+	maximized := parseLanguageTag(tag).maximize()
+
+	for _, candidate := range []languageTag{
+		{maximized.Language, "", ""},
+		{maximized.Language, "", maximized.Region},
+		{maximized.Language, maximized.Script, ""},
+	} {
+		if candidate.maximize() == maximized {
+			return candidate.String()
+		}
+	}
+
+	return maximized.String()
+}
+
+// matchField returns true if the value matches the field of a language
+// match rule: * is any value, $var and $!var are the regions of a
+// variable.
+func matchField(supplemental *dto.Supplemental, pattern, value string) bool {
+	switch {
+	case pattern == "*":
+		return true
+	case strings.HasPrefix(pattern, "$!"):
+		return !slices.Contains(supplemental.GetMatchVariable(pattern[2:]), value)
+	case strings.HasPrefix(pattern, "$"):
+		return slices.Contains(supplemental.GetMatchVariable(pattern[1:]), value)
+	}
+
+	return pattern == value
+}
+
+func matchRule(supplemental *dto.Supplemental, desired, supported, desiredValues, supportedValues []string) bool {
+	for i := range desiredValues {
+		if !matchField(supplemental, desired[i], desiredValues[i]) || !matchField(supplemental, supported[i], supportedValues[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getRuleDistance returns the distance of the first rule matching the
+// values, the rules are symmetric unless they are oneway.
+func getRuleDistance(desired, supported []string) int {
+	supplemental := dto.GetSupplemental()
+
+	for _, rule := range supplemental.GetLanguageMatches() {
+		desiredRule := strings.Split(rule.Desired, "_")
+		supportedRule := strings.Split(rule.Supported, "_")
+
+		if len(desiredRule) != len(desired) || len(supportedRule) != len(supported) {
+			continue
+		}
+
+		if matchRule(supplemental, desiredRule, supportedRule, desired, supported) {
+			return rule.Distance
+		}
+
+		if !rule.Oneway && matchRule(supplemental, desiredRule, supportedRule, supported, desired) {
+			return rule.Distance
+		}
+	}
+
+	// the default CLDR distances
+	switch len(desired) {
+	case 1:
+		return 80
+	case 2:
+		return 50
+	}
+
+	return 4
+}
+
+// GetLanguageDistance returns the distance between two locales, computed by
+// comparing their language, script and region with the CLDR language
+// matching rules: 0 is the same locale, pt_BR and pt_PT is 5, and a
+// distance greater than MatchThreshold is a different language.
+// http://www.unicode.org/reports/tr35/#EnhancedLanguageMatching
+func GetLanguageDistance(desired, supported string) int {
+	d := parseLanguageTag(desired).maximize()
+	s := parseLanguageTag(supported).maximize()
+
+	distance := 0
+
+	if d.Language != s.Language {
+		distance += getRuleDistance([]string{d.Language}, []string{s.Language})
+	}
+
+	if d.Script != s.Script {
+		distance += getRuleDistance([]string{d.Language, d.Script}, []string{s.Language, s.Script})
+	}
+
+	if d.Region != s.Region {
+		distance += getRuleDistance([]string{d.Language, d.Script, d.Region}, []string{s.Language, s.Script, s.Region})
+	}
+
+	return distance
+}
+
+func getConfidence(distance int) Confidence {
+	switch {
+	case distance == 0:
+		return ExactConfidence
+	case distance < 10:
+		return HighConfidence
+	case distance < MatchThreshold:
+		return LowConfidence
+	}
+
+	return NoConfidence
+}
+
+// Negotiate returns the supported locales matching the requested ones, the
+// best match first. The requested locales are ordered by preference, a
+// close match of a requested locale is better than an exact match of the
+// next one, and the paradigm locales (en, es_419, pt_BR, ...) are preferred
+// on equal distances. The locales with a distance greater than
+// MatchThreshold are not returned.
+func Negotiate(requested []string, supported []string) []LocaleMatch {
+	paradigms := map[string]bool{}
+	for _, p := range dto.GetSupplemental().GetParadigmLocales() {
+		paradigms[AddLikelySubtags(p)] = true
+	}
+
+	type ranked struct {
+		match    LocaleMatch
+		score    int
+		paradigm bool
+		index    int
+	}
+
+	matches := []ranked{}
+	for i, s := range supported {
+		var best *ranked
+
+		for j, r := range requested {
+			distance := GetLanguageDistance(r, s)
+			if distance >= MatchThreshold {
+				continue
+			}
+
+			score := distance + j*matchDemotion
+			if best == nil || score < best.score {
+				best = &ranked{
+					match: LocaleMatch{
+						Requested:  r,
+						Supported:  s,
+						Distance:   distance,
+						Confidence: getConfidence(distance),
+					},
+					score:    score,
+					paradigm: paradigms[AddLikelySubtags(s)],
+					index:    i,
+				}
+			}
+		}
+
+		if best != nil {
+			matches = append(matches, *best)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+
+		if matches[i].paradigm != matches[j].paradigm {
+			return matches[i].paradigm
+		}
+
+		return matches[i].index < matches[j].index
+	})
+
+	result := make([]LocaleMatch, len(matches))
+	for i, m := range matches {
+		result[i] = m.match
+	}
+
+	return result
+}
+
+// AcceptLanguage is a language of the Accept-Language header, with its
+// quality value.
+type AcceptLanguage struct {
+	Tag     string
+	Quality float64
+}
+
+// ParseAcceptLanguage parses the Accept-Language header, the languages are
+// sorted by quality. The wildcard, the languages with a quality of 0 and
+// the invalid entries are skipped: "fr-CH, fr;q=0.9, en;q=0.8".
+func ParseAcceptLanguage(header string) []AcceptLanguage {
+	languages := []AcceptLanguage{}
+
+	for _, entry := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(entry, ";")
+
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.TrimSpace(name) != "q" {
+				continue
+			}
+
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || q < 0 || q > 1 {
+				q = 0
+			}
+
+			quality = q
+		}
+
+		if quality == 0 {
+			continue
+		}
+
+		languages = append(languages, AcceptLanguage{Tag: tag, Quality: quality})
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].Quality > languages[j].Quality
+	})
+
+	return languages
+}
+
+// NegotiateAcceptLanguage returns the supported locales matching the
+// Accept-Language header, the best match first.
+func NegotiateAcceptLanguage(header string, supported []string) []LocaleMatch {
+	requested := []string{}
+	for _, language := range ParseAcceptLanguage(header) {
+		requested = append(requested, language.Tag)
+	}
+
+	return Negotiate(requested, supported)
+}
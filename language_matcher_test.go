@@ -0,0 +1,84 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales_test
+
+import (
+	"testing"
+
+	"github.com/rande/golocales"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AddLikelySubtags(t *testing.T) {
+	assert.Equal(t, "fr_Latn_FR", golocales.AddLikelySubtags("fr"))
+	assert.Equal(t, "fr_Latn_CA", golocales.AddLikelySubtags("fr-CA"))
+	assert.Equal(t, "zh_Hant_TW", golocales.AddLikelySubtags("zh-TW"))
+	assert.Equal(t, "zh_Hant_TW", golocales.AddLikelySubtags("und-Hant"))
+	assert.Equal(t, "en_Latn_US", golocales.AddLikelySubtags("und"))
+	assert.Equal(t, "sr_Latn_ME", golocales.AddLikelySubtags("sr_ME"))
+}
+
+func Test_RemoveLikelySubtags(t *testing.T) {
+	assert.Equal(t, "fr", golocales.RemoveLikelySubtags("fr_Latn_FR"))
+	assert.Equal(t, "fr_CA", golocales.RemoveLikelySubtags("fr-Latn-CA"))
+	assert.Equal(t, "zh_TW", golocales.RemoveLikelySubtags("zh_Hant_TW"))
+	assert.Equal(t, "sr_Latn", golocales.RemoveLikelySubtags("sr_Latn_RS"))
+}
+
+func Test_GetLanguageDistance(t *testing.T) {
+	assert.Equal(t, 0, golocales.GetLanguageDistance("fr", "fr_FR"))
+	assert.Equal(t, 4, golocales.GetLanguageDistance("fr_CA", "fr_FR"))
+	assert.Equal(t, 5, golocales.GetLanguageDistance("pt_BR", "pt_PT"))
+	assert.Equal(t, 4, golocales.GetLanguageDistance("es_MX", "es_419"))
+	assert.Equal(t, 1, golocales.GetLanguageDistance("nb", "no"))
+	// oneway rules, zh_Hant_TW and zh_Hans_CN also have a different region
+	assert.Equal(t, 20, golocales.GetLanguageDistance("ca", "es"))
+	assert.Equal(t, 80, golocales.GetLanguageDistance("es", "ca"))
+	assert.Equal(t, 23, golocales.GetLanguageDistance("zh_TW", "zh"))
+	assert.Equal(t, 84, golocales.GetLanguageDistance("pt_BR", "es"))
+}
+
+func Test_Negotiate(t *testing.T) {
+	matches := golocales.Negotiate([]string{"pt-BR"}, []string{"es", "pt-PT"})
+
+	assert.Equal(t, []golocales.LocaleMatch{
+		{Requested: "pt-BR", Supported: "pt-PT", Distance: 5, Confidence: golocales.HighConfidence},
+	}, matches)
+
+	// a close match of the first requested locale is better than an exact
+	// match of the next one
+	matches = golocales.Negotiate([]string{"fr-CA", "en"}, []string{"en", "fr-FR", "de"})
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, "fr-FR", matches[0].Supported)
+		assert.Equal(t, "en", matches[1].Supported)
+		assert.Equal(t, golocales.ExactConfidence, matches[1].Confidence)
+	}
+
+	assert.Empty(t, golocales.Negotiate([]string{"de"}, []string{"fr", "en"}))
+}
+
+func Test_ParseAcceptLanguage(t *testing.T) {
+	languages := golocales.ParseAcceptLanguage("en;q=0.8, fr-CH, de;q=0.7, fr;q=0.9, *;q=0.5, es;q=0, it;q=foo")
+
+	assert.Equal(t, []golocales.AcceptLanguage{
+		{Tag: "fr-CH", Quality: 1},
+		{Tag: "fr", Quality: 0.9},
+		{Tag: "en", Quality: 0.8},
+		{Tag: "de", Quality: 0.7},
+	}, languages)
+
+	assert.Empty(t, golocales.ParseAcceptLanguage(""))
+}
+
+func Test_NegotiateAcceptLanguage(t *testing.T) {
+	matches := golocales.NegotiateAcceptLanguage("de-CH, fr;q=0.9, en;q=0.8", []string{"en", "fr-CA"})
+
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, "fr-CA", matches[0].Supported)
+		assert.Equal(t, "fr", matches[0].Requested)
+		assert.Equal(t, golocales.HighConfidence, matches[0].Confidence)
+	}
+}
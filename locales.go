@@ -4,3 +4,69 @@
 // license that can be found in the LICENSE file.
 
 package golocales
+
+import (
+	"strings"
+
+	"github.com/rande/golocales/dto"
+	"github.com/rande/golocales/locales"
+)
+
+// CLDRVersion returns the CLDR release of the generated locales, ie: 44.
+func CLDRVersion() string {
+	return locales.CLDRVersion
+}
+
+// Get returns the locale of the tag, the BCP 47 (fr-CA) and the CLDR
+// (fr_CA) forms are accepted, the extensions are ignored: fr-CA-u-ca-gregory
+// is fr_CA, see NewDateFormatterForTag to use the calendar of the tag. The
+// locale is only available if its package is imported, ie:
+// golocales/locales/fr_CA, or golocales/locales/all for all the locales.
+// The value is nil if the locale is not available.
+func Get(tag string) *dto.Locale {
+	return dto.GetRegisteredLocale(NormalizeTag(tag))
+}
+
+// Available returns the CLDR names of the available locales, sorted.
+func Available() []string {
+	return dto.GetRegisteredLocaleNames()
+}
+
+// NormalizeTag returns the CLDR name of a language tag: the subtags are
+// separated by an underscore, the language is lowercase, the script is
+// titlecase and the region uppercase: "FR-ca" is fr_CA and "sr-latn" is
+// sr_Latn. The undetermined language (und) is the root locale.
+func NormalizeTag(tag string) string {
+	parts := strings.FieldsFunc(tag, func(c rune) bool {
+		return c == '-' || c == '_'
+	})
+
+	subtags := []string{}
+	for i, part := range parts {
+		if len(part) == 1 {
+			// a singleton starts the extensions: -u-ca-gregory
+			break
+		}
+
+		switch {
+		case i == 0:
+			part = strings.ToLower(part)
+		case len(part) == 4 && strings.IndexFunc(part, isDigit) == -1:
+			part = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		default:
+			part = strings.ToUpper(part)
+		}
+
+		subtags = append(subtags, part)
+	}
+
+	if len(subtags) == 0 || len(subtags) == 1 && subtags[0] == "und" {
+		return "root"
+	}
+
+	return strings.Join(subtags, "_")
+}
+
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
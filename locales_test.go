@@ -0,0 +1,78 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales_test
+
+import (
+	"testing"
+
+	"github.com/rande/golocales"
+	"github.com/rande/golocales/locales/en_NL"
+	"github.com/rande/golocales/locales/fr"
+	"github.com/rande/golocales/locales/fr_CA"
+	"github.com/rande/golocales/locales/root"
+	"github.com/rande/golocales/locales/sr_Latn"
+	"github.com/rande/golocales/locales/zh_Hant_HK"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NormalizeTag(t *testing.T) {
+	assert.Equal(t, "fr_CA", golocales.NormalizeTag("fr-CA"))
+	assert.Equal(t, "fr_CA", golocales.NormalizeTag("FR_ca"))
+	assert.Equal(t, "fr_CA", golocales.NormalizeTag("fr-CA-u-ca-gregory"))
+	assert.Equal(t, "sr_Latn_RS", golocales.NormalizeTag("sr-latn-rs"))
+	assert.Equal(t, "es_419", golocales.NormalizeTag("es-419"))
+	assert.Equal(t, "root", golocales.NormalizeTag("und"))
+	assert.Equal(t, "root", golocales.NormalizeTag(""))
+}
+
+func Test_Get(t *testing.T) {
+	assert.Same(t, fr.GetLocale(), golocales.Get("fr"))
+	assert.Same(t, fr_CA.GetLocale(), golocales.Get("fr-CA"))
+	assert.Same(t, fr_CA.GetLocale(), golocales.Get("fr_CA"))
+	assert.Same(t, root.GetLocale(), golocales.Get("und"))
+	assert.Nil(t, golocales.Get("xx-YY"))
+}
+
+func Test_CLDRVersion(t *testing.T) {
+	assert.Regexp(t, `^\d+(\.\d+)*$`, golocales.CLDRVersion())
+}
+
+func Test_Available(t *testing.T) {
+	available := golocales.Available()
+
+	assert.Contains(t, available, "root")
+	assert.Contains(t, available, "fr")
+	assert.Contains(t, available, "fr_CA")
+	assert.IsIncreasing(t, available)
+}
+
+func Test_Parent_Locales(t *testing.T) {
+	names := []string{}
+	for l := en_NL.GetLocale().Parent; l != nil; l = l.Parent {
+		names = append(names, l.Name)
+	}
+
+	assert.Equal(t, []string{"en_150", "en_001", "en", "root"}, names)
+}
+
+func Test_Script_Locales(t *testing.T) {
+	l := zh_Hant_HK.GetLocale()
+
+	assert.Equal(t, "zh_Hant_HK", l.Name)
+	assert.Equal(t, "zh", l.Language)
+	assert.Equal(t, "Hant", l.Script)
+	assert.Equal(t, "HK", l.Territory)
+	assert.Equal(t, "", l.Variant)
+
+	// zh_Hant inherits from root, not from zh
+	assert.Equal(t, "zh_Hant", l.Parent.Name)
+	assert.Equal(t, "root", l.Parent.Parent.Name)
+
+	assert.Equal(t, "sr", sr_Latn.GetLocale().Language)
+	assert.Equal(t, "Latn", sr_Latn.GetLocale().Script)
+	assert.Equal(t, "", sr_Latn.GetLocale().Territory)
+	assert.Same(t, sr_Latn.GetLocale(), golocales.Get("sr-Latn"))
+}
@@ -0,0 +1,233 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rande/golocales/dto"
+)
+
+// The plural categories, the order matters as the rules are evaluated
+// in this order, "other" is the fallback value.
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+var pluralCategories = []string{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany}
+
+// pluralOperands are the operands used by the CLDR plural rules.
+// http://www.unicode.org/reports/tr35/tr35-numbers.html#Operands
+type pluralOperands struct {
+	n float64 // absolute value of the source number
+	i float64 // integer digits of n
+	v float64 // number of visible fraction digits in n, with trailing zeros
+	w float64 // number of visible fraction digits in n, without trailing zeros
+	f float64 // visible fraction digits in n, with trailing zeros
+	t float64 // visible fraction digits in n, without trailing zeros
+	e float64 // compact decimal exponent value, always 0 for now
+}
+
+func newPluralOperands(number string) (*pluralOperands, error) {
+	number = strings.TrimLeft(strings.TrimSpace(number), "+-")
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return nil, InvalidNumberError{number}
+	}
+
+	ops := &pluralOperands{n: n}
+
+	integer, fraction, _ := strings.Cut(number, ".")
+	ops.i, _ = strconv.ParseFloat(integer, 64)
+
+	if fraction != "" {
+		trimmed := strings.TrimRight(fraction, "0")
+		ops.v = float64(len(fraction))
+		ops.w = float64(len(trimmed))
+		ops.f, _ = strconv.ParseFloat(fraction, 64)
+		if trimmed != "" {
+			ops.t, _ = strconv.ParseFloat(trimmed, 64)
+		}
+	}
+
+	return ops, nil
+}
+
+func (o *pluralOperands) get(name byte) float64 {
+	switch name {
+	case 'n':
+		return o.n
+	case 'i':
+		return o.i
+	case 'v':
+		return o.v
+	case 'w':
+		return o.w
+	case 'f':
+		return o.f
+	case 't':
+		return o.t
+	case 'c', 'e':
+		return o.e
+	}
+
+	return 0
+}
+
+// pluralRange is a value (From == To) or an inclusive range.
+type pluralRange struct {
+	From float64
+	To   float64
+}
+
+type pluralRelation struct {
+	Operand byte
+	Modulo  float64
+	Negate  bool
+	Ranges  []pluralRange
+}
+
+// pluralCondition is a list of "or" conditions, each one is a list
+// of relations joined by "and".
+type pluralCondition [][]*pluralRelation
+
+func (r *pluralRelation) match(ops *pluralOperands) bool {
+	value := ops.get(r.Operand)
+	if r.Modulo > 0 {
+		value = math.Mod(value, r.Modulo)
+	}
+
+	found := false
+	for _, rg := range r.Ranges {
+		if rg.From == rg.To && value == rg.From {
+			found = true
+			break
+		}
+
+		// a range only matches integer values
+		if value == math.Trunc(value) && value >= rg.From && value <= rg.To {
+			found = true
+			break
+		}
+	}
+
+	return found != r.Negate
+}
+
+func (c pluralCondition) match(ops *pluralOperands) bool {
+	for _, and := range c {
+		matched := true
+		for _, relation := range and {
+			if !relation.match(ops) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parsePluralRule parses a rule like "i = 1 and v = 0 or n % 10 = 2..4"
+func parsePluralRule(rule string) pluralCondition {
+	condition := pluralCondition{}
+
+	for _, or := range strings.Split(rule, " or ") {
+		relations := []*pluralRelation{}
+
+		for _, and := range strings.Split(or, " and ") {
+			relation := &pluralRelation{}
+
+			expr, ranges, found := strings.Cut(and, "!=")
+			if found {
+				relation.Negate = true
+			} else {
+				expr, ranges, _ = strings.Cut(and, "=")
+			}
+
+			expr = strings.TrimSpace(expr)
+			if operand, modulo, ok := strings.Cut(expr, "%"); ok {
+				relation.Modulo, _ = strconv.ParseFloat(strings.TrimSpace(modulo), 64)
+				expr = strings.TrimSpace(operand)
+			}
+
+			if len(expr) > 0 {
+				relation.Operand = expr[0]
+			}
+
+			for _, r := range strings.Split(ranges, ",") {
+				from, to, ok := strings.Cut(strings.TrimSpace(r), "..")
+				if !ok {
+					to = from
+				}
+
+				rg := pluralRange{}
+				rg.From, _ = strconv.ParseFloat(from, 64)
+				rg.To, _ = strconv.ParseFloat(to, 64)
+
+				relation.Ranges = append(relation.Ranges, rg)
+			}
+
+			relations = append(relations, relation)
+		}
+
+		condition = append(condition, relations)
+	}
+
+	return condition
+}
+
+var pluralCache sync.Map // rule => pluralCondition
+
+func getPluralCondition(rule string) pluralCondition {
+	if c, ok := pluralCache.Load(rule); ok {
+		return c.(pluralCondition)
+	}
+
+	c := parsePluralRule(rule)
+	pluralCache.Store(rule, c)
+
+	return c
+}
+
+// GetPluralCategory returns the cardinal plural category (zero, one, two,
+// few, many or other) of a number for the given locale. The number is a
+// decimal string, so the visible fraction digits are kept: "1" and "1.0"
+// can have different categories.
+func GetPluralCategory(locale *dto.Locale, number string) (string, error) {
+	ops, err := newPluralOperands(number)
+	if err != nil {
+		return PluralOther, err
+	}
+
+	rules := locale.GetPluralRules()
+
+	for _, category := range pluralCategories {
+		rule, ok := rules[category]
+		if !ok || rule == "" {
+			continue
+		}
+
+		if getPluralCondition(rule).match(ops) {
+			return category, nil
+		}
+	}
+
+	return PluralOther, nil
+}
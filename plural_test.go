@@ -0,0 +1,50 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales_test
+
+import (
+	"testing"
+
+	"github.com/rande/golocales"
+	"github.com/rande/golocales/dto"
+	"github.com/rande/golocales/locales/en"
+	"github.com/rande/golocales/locales/fr"
+	"github.com/rande/golocales/locales/fr_CA"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetPluralCategory(t *testing.T) {
+	tests := []struct {
+		number string
+		want   string
+		locale *dto.Locale
+	}{
+		{"1", golocales.PluralOne, en.GetLocale()},
+		{"1.0", golocales.PluralOther, en.GetLocale()},
+		{"0", golocales.PluralOther, en.GetLocale()},
+		{"-1", golocales.PluralOne, en.GetLocale()},
+
+		{"0", golocales.PluralOne, fr.GetLocale()},
+		{"1.5", golocales.PluralOne, fr.GetLocale()},
+		{"2", golocales.PluralOther, fr.GetLocale()},
+		{"1000000", golocales.PluralMany, fr.GetLocale()},
+
+		// rules are inherited from the language
+		{"1.5", golocales.PluralOne, fr_CA.GetLocale()},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			got, err := golocales.GetPluralCategory(tt.locale, tt.number)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := golocales.GetPluralCategory(en.GetLocale(), "INVALID")
+	assert.Error(t, err)
+}
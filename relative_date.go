@@ -0,0 +1,158 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales
+
+import (
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rande/golocales/dto"
+)
+
+type RelativeDateOptions struct {
+	// DateStyle is the date format used when the day is more than a week
+	// away (short, medium, long or full), it also selects the glue pattern.
+	// Defaults to "medium".
+	DateStyle string
+	// TimeStyle is the time format (short, medium, long or full).
+	// Defaults to "short".
+	TimeStyle string
+	// KeepCase keeps the case of the labels, by default the first letter is
+	// uppercased as the output usually starts a line: "Yesterday at 18:04".
+	// Defaults to false.
+	KeepCase bool
+}
+
+func CreateRelativeDateOptions() *RelativeDateOptions {
+	return &RelativeDateOptions{
+		DateStyle: "medium",
+		TimeStyle: "short",
+		KeepCase:  false,
+	}
+}
+
+// RelativeDateFormatter formats a date relatively to another one, using
+// the relative day labels: "Today at 15:30", "Tomorrow at 9 AM", "Monday at
+// 10:00", or the full date when the days are more than a week away.
+type RelativeDateFormatter struct {
+	locale *dto.Locale
+}
+
+// NewRelativeDateFormatter creates a new RelativeDateFormatter for the given locale.
+func NewRelativeDateFormatter(locale *dto.Locale) *RelativeDateFormatter {
+	return &RelativeDateFormatter{
+		locale: locale,
+	}
+}
+
+// Locale returns the locale.
+func (f *RelativeDateFormatter) GetLocale() *dto.Locale {
+	return f.locale
+}
+
+// Format formats t relatively to now, both values are converted into the
+// location before computing the distance in days. A nil location uses the
+// location of t. The empty styles of the options use the default ones.
+func (f *RelativeDateFormatter) Format(t, now time.Time, location *time.Location, options ...*RelativeDateOptions) string {
+	formattingOptions := CreateRelativeDateOptions()
+	if len(options) > 0 {
+		formattingOptions.DateStyle = ifEmpty(options[0].DateStyle, formattingOptions.DateStyle)
+		formattingOptions.TimeStyle = ifEmpty(options[0].TimeStyle, formattingOptions.TimeStyle)
+		formattingOptions.KeepCase = options[0].KeepCase
+	}
+
+	t = inLocation(t, location)
+	now = now.In(t.Location())
+
+	days := daysBetween(now, t)
+
+	date := ""
+	if days >= -1 && days <= 1 {
+		date = f.locale.GetFieldRelative("day", strconv.Itoa(days))
+	}
+
+	if date == "" && days > -7 && days < 7 {
+		if labels := f.locale.GetCalendarLabels(DefaultCalendar, "d_format_wide"); len(labels) == 7 {
+			date = labels[t.Weekday()]
+		}
+	}
+
+	// the date is not relative, so the standard glue is used
+	glue := ""
+	if date == "" {
+		date = f.format("date_"+formattingOptions.DateStyle, t)
+		glue = f.locale.GetCalendarPattern(DefaultCalendar, "datetime_"+formattingOptions.DateStyle)
+	} else {
+		glue = f.locale.GetCalendarPattern(DefaultCalendar, "datetime_"+formattingOptions.DateStyle+"_atTime")
+		if glue == "" {
+			glue = f.locale.GetCalendarPattern(DefaultCalendar, "datetime_"+formattingOptions.DateStyle)
+		}
+	}
+
+	if glue == "" {
+		glue = "{1} {0}"
+	}
+
+	result := combineDateTime(glue, date, f.format("time_"+formattingOptions.TimeStyle, t))
+
+	if !formattingOptions.KeepCase {
+		result = capitalize(result)
+	}
+
+	return result
+}
+
+// format formats the time with the date or time style, the time must
+// already be in the expected location.
+func (f *RelativeDateFormatter) format(name string, t time.Time) string {
+	pattern := f.locale.GetCalendarPattern(DefaultCalendar, name)
+	if pattern == "" {
+		return ""
+	}
+
+	return formatDatePattern(f.locale, DefaultCalendar, compileDatePattern(pattern), t)
+}
+
+// daysBetween returns the number of calendar days from a to b, the time of
+// the day is ignored.
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+
+	from := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	to := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// inLocation converts the time into the location, a nil location keeps the
+// location of the time.
+func inLocation(t time.Time, location *time.Location) time.Time {
+	if location == nil {
+		return t
+	}
+
+	return t.In(location)
+}
+
+func ifEmpty(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+
+	return string(unicode.ToUpper(r)) + s[size:]
+}
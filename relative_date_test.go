@@ -0,0 +1,73 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rande/golocales"
+	"github.com/rande/golocales/locales/fr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RelativeDateFormatter_Format(t *testing.T) {
+	// Wednesday, 10 January 2024 at 13:00 in Paris
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{now.Add(-18 * time.Hour), "Hier à 19:00"},
+		{now.Add(2 * time.Hour), "Aujourd’hui à 15:00"},
+		{now.Add(20 * time.Hour), "Demain à 09:00"},
+		{now.Add(-72 * time.Hour), "Dimanche à 13:00"},
+		{now.Add(30 * 24 * time.Hour), "9 février 2024, 13:00"},
+	}
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+
+	formatter := golocales.NewRelativeDateFormatter(fr.GetLocale())
+
+	options := golocales.CreateRelativeDateOptions()
+	options.DateStyle = "long"
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			assert.Equal(t, tt.want, formatter.Format(tt.t, now, paris, options))
+		})
+	}
+}
+
+func Test_RelativeDateFormatter_TimeZone(t *testing.T) {
+	// 23:30 in UTC is already the next day in Paris
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	tm := time.Date(2024, 1, 10, 23, 30, 0, 0, time.UTC)
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+
+	formatter := golocales.NewRelativeDateFormatter(fr.GetLocale())
+
+	options := golocales.CreateRelativeDateOptions()
+	options.KeepCase = true
+
+	assert.Equal(t, "demain, 00:30", formatter.Format(tm, now, paris, options))
+	assert.Equal(t, "aujourd’hui, 23:30", formatter.Format(tm, now, time.UTC, options))
+	// a nil location uses the location of the time
+	assert.Equal(t, "demain, 00:30", formatter.Format(tm.In(paris), now, nil, options))
+}
+
+func Test_RelativeDateFormatter_DefaultOptions(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	tm := now.Add(-24 * time.Hour)
+
+	formatter := golocales.NewRelativeDateFormatter(fr.GetLocale())
+
+	// the empty fields use the default values
+	assert.Equal(t, formatter.Format(tm, now, time.UTC), formatter.Format(tm, now, time.UTC, &golocales.RelativeDateOptions{}))
+	assert.Equal(t, "Hier, 12:00", formatter.Format(tm, now, time.UTC, &golocales.RelativeDateOptions{}))
+}
@@ -0,0 +1,206 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rande/golocales/dto"
+)
+
+// RelativeUnit is the unit of a relative time, the value matches the
+// CLDR field type.
+type RelativeUnit string
+
+const (
+	RelativeYear    RelativeUnit = "year"
+	RelativeQuarter RelativeUnit = "quarter"
+	RelativeMonth   RelativeUnit = "month"
+	RelativeWeek    RelativeUnit = "week"
+	RelativeDay     RelativeUnit = "day"
+	RelativeHour    RelativeUnit = "hour"
+	RelativeMinute  RelativeUnit = "minute"
+	RelativeSecond  RelativeUnit = "second"
+)
+
+// RelativeStyle represents the width of the relative time output.
+type RelativeStyle uint8
+
+const (
+	// RelativeStyleLong uses the long form, ie: "in 3 days".
+	RelativeStyleLong RelativeStyle = iota
+	// RelativeStyleShort uses the short form, ie: "in 3 days" or "in 3 hr.".
+	RelativeStyleShort
+	// RelativeStyleNarrow uses the narrow form, ie: "+3 d" in some locales.
+	RelativeStyleNarrow
+)
+
+// the units used to split a duration, the month and year values are
+// approximations as a duration is not attached to a calendar, see
+// FormatDuration.
+var relativeDurationUnits = []struct {
+	unit     RelativeUnit
+	duration time.Duration
+}{
+	{RelativeYear, 365 * 24 * time.Hour},
+	{RelativeMonth, 30 * 24 * time.Hour},
+	{RelativeWeek, 7 * 24 * time.Hour},
+	{RelativeDay, 24 * time.Hour},
+	{RelativeHour, time.Hour},
+	{RelativeMinute, time.Minute},
+	{RelativeSecond, time.Second},
+}
+
+type RelativeTimeOptions struct {
+	// Style defines the width of the labels (long, short or narrow).
+	// Defaults to RelativeStyleLong.
+	Style RelativeStyle
+	// Natural uses the natural label when available ("tomorrow"), instead
+	// of the numeric output ("in 1 day").
+	// Defaults to false.
+	Natural bool
+}
+
+func CreateRelativeTimeOptions() *RelativeTimeOptions {
+	return &RelativeTimeOptions{
+		Style:   RelativeStyleLong,
+		Natural: false,
+	}
+}
+
+// RelativeTimeFormatter formats relative times, ie: "in 3 days", "yesterday".
+type RelativeTimeFormatter struct {
+	locale *dto.Locale
+	amount *AmountFormatter
+}
+
+// NewRelativeTimeFormatter creates a new RelativeTimeFormatter for the given locale.
+func NewRelativeTimeFormatter(locale *dto.Locale) *RelativeTimeFormatter {
+	return &RelativeTimeFormatter{
+		locale: locale,
+		amount: NewAmountFormatter(locale),
+	}
+}
+
+// Locale returns the locale.
+func (f *RelativeTimeFormatter) GetLocale() *dto.Locale {
+	return f.locale
+}
+
+// Format formats a value expressed in the given unit, a negative value
+// is in the past: -1 day is "1 day ago" or "yesterday".
+func (f *RelativeTimeFormatter) Format(value float64, unit RelativeUnit, options ...*RelativeTimeOptions) string {
+	formattingOptions := CreateRelativeTimeOptions()
+	if len(options) > 0 {
+		formattingOptions = options[0]
+	}
+
+	future := !math.Signbit(value)
+	value = math.Abs(value)
+
+	if formattingOptions.Natural && value == math.Trunc(value) {
+		offset := strconv.FormatFloat(value, 'f', 0, 64)
+		if !future && value != 0 {
+			offset = "-" + offset
+		}
+
+		if label := f.getRelative(unit, formattingOptions.Style, offset); label != "" {
+			return label
+		}
+	}
+
+	number := strconv.FormatFloat(value, 'f', -1, 64)
+	count, _ := GetPluralCategory(f.locale, number)
+
+	pattern := f.getPattern(unit, formattingOptions.Style, future, count)
+
+	return strings.Replace(pattern, "{0}", f.formatNumber(number), 1)
+}
+
+// FormatDuration formats a duration with the largest unit fitting in the
+// duration, the value is truncated: 36 hours is "in 1 day". A negative
+// duration is in the past. A duration is not attached to a date, so a month
+// is approximated as 30 days and a year as 365 days, and the quarters are
+// never used: 100 days is "in 3 months". Use Format with the number of
+// calendar months or years computed from the dates for an exact value.
+func (f *RelativeTimeFormatter) FormatDuration(d time.Duration, options ...*RelativeTimeOptions) string {
+	abs := d
+	if abs < 0 {
+		abs = -abs
+	}
+
+	for _, u := range relativeDurationUnits {
+		if abs < u.duration && u.unit != RelativeSecond {
+			continue
+		}
+
+		value := math.Trunc(float64(abs / u.duration))
+		if d < 0 {
+			value = -value
+		}
+
+		return f.Format(value, u.unit, options...)
+	}
+
+	return ""
+}
+
+// getRelative returns the natural label for an offset, ie: "-1" => "yesterday".
+func (f *RelativeTimeFormatter) getRelative(unit RelativeUnit, style RelativeStyle, offset string) string {
+	for _, name := range relativeFieldNames(unit, style) {
+		if label := f.locale.GetFieldRelative(name, offset); label != "" {
+			return label
+		}
+	}
+
+	return ""
+}
+
+// getPattern returns the future or past pattern for the plural count, the
+// "other" count is used if the locale does not define the requested one.
+func (f *RelativeTimeFormatter) getPattern(unit RelativeUnit, style RelativeStyle, future bool, count string) string {
+	for _, name := range relativeFieldNames(unit, style) {
+		if pattern := f.locale.GetFieldRelativeTime(name, future, count); pattern != "" {
+			return pattern
+		}
+
+		if pattern := f.locale.GetFieldRelativeTime(name, future, PluralOther); pattern != "" {
+			return pattern
+		}
+	}
+
+	return "{0}"
+}
+
+func (f *RelativeTimeFormatter) formatNumber(number string) string {
+	amount, err := NewAmount(number)
+	if err != nil {
+		return number
+	}
+
+	options := CreateFormattingOptions()
+	options.MinDigits = 0
+	options.MaxDigits = 3
+
+	return f.amount.formatNumber(amount, options)
+}
+
+// relativeFieldNames returns the field names to look up, from the
+// requested width to the long one, as the narrow and short fields are
+// often an alias to the wider field.
+func relativeFieldNames(unit RelativeUnit, style RelativeStyle) []string {
+	switch style {
+	case RelativeStyleNarrow:
+		return []string{string(unit) + "-narrow", string(unit) + "-short", string(unit)}
+	case RelativeStyleShort:
+		return []string{string(unit) + "-short", string(unit)}
+	}
+
+	return []string{string(unit)}
+}
@@ -0,0 +1,71 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rande/golocales"
+	"github.com/rande/golocales/dto"
+	"github.com/rande/golocales/locales/en"
+	"github.com/rande/golocales/locales/fr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RelativeTimeFormatter_Format(t *testing.T) {
+	tests := []struct {
+		value   float64
+		unit    golocales.RelativeUnit
+		natural bool
+		want    string
+		locale  *dto.Locale
+	}{
+		{3, golocales.RelativeDay, false, "in 3 days", en.GetLocale()},
+		{1, golocales.RelativeDay, false, "in 1 day", en.GetLocale()},
+		{-2, golocales.RelativeHour, false, "2 hours ago", en.GetLocale()},
+		{1, golocales.RelativeDay, true, "tomorrow", en.GetLocale()},
+		{-1, golocales.RelativeDay, true, "yesterday", en.GetLocale()},
+		// no natural label, fallback to the numeric output
+		{-3, golocales.RelativeDay, true, "3 days ago", en.GetLocale()},
+
+		{3, golocales.RelativeDay, false, "dans 3 jours", fr.GetLocale()},
+		{-2, golocales.RelativeHour, false, "il y a 2 heures", fr.GetLocale()},
+		{-1, golocales.RelativeDay, true, "hier", fr.GetLocale()},
+		{-2, golocales.RelativeDay, true, "avant-hier", fr.GetLocale()},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			formatter := golocales.NewRelativeTimeFormatter(tt.locale)
+
+			options := golocales.CreateRelativeTimeOptions()
+			options.Natural = tt.natural
+
+			assert.Equal(t, tt.want, formatter.Format(tt.value, tt.unit, options))
+		})
+	}
+}
+
+func Test_RelativeTimeFormatter_FormatDuration(t *testing.T) {
+	formatter := golocales.NewRelativeTimeFormatter(en.GetLocale())
+
+	assert.Equal(t, "in 1 day", formatter.FormatDuration(36*time.Hour))
+	assert.Equal(t, "5 minutes ago", formatter.FormatDuration(-5*time.Minute))
+	assert.Equal(t, "in 0 seconds", formatter.FormatDuration(0))
+	// a month is 30 days, the quarters are not used
+	assert.Equal(t, "in 3 months", formatter.FormatDuration(100*24*time.Hour))
+	assert.Equal(t, "1 year ago", formatter.FormatDuration(-400*24*time.Hour))
+
+	// the zero value options are the default ones
+	assert.Equal(t, "in 1 day", formatter.FormatDuration(24*time.Hour, &golocales.RelativeTimeOptions{}))
+
+	options := golocales.CreateRelativeTimeOptions()
+	options.Natural = true
+
+	assert.Equal(t, "now", formatter.FormatDuration(0, options))
+	assert.Equal(t, "yesterday", formatter.FormatDuration(-24*time.Hour, options))
+}
@@ -0,0 +1,389 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rande/golocales/dto"
+)
+
+// The weights used to compute the distance between two skeletons, a field
+// present in the candidate but not requested is worse than a missing one,
+// as the output would display an unexpected value.
+// http://www.unicode.org/reports/tr35/tr35-dates.html#Matching_Skeletons
+const (
+	skeletonExtraField     = 0x10000
+	skeletonMissingField   = 0x1000
+	skeletonMismatchType   = 0x100
+	skeletonMismatchLetter = 0x10
+)
+
+// skeleton is a parsed skeleton, indexed by field group.
+type skeleton map[rune]patternItem
+
+// skeletonGroup returns the group of a field, the letters of a group
+// represent the same value with a different presentation, ie: M and L.
+// The day period (a) is not part of a skeleton, as it is implied by the
+// 12 hours format.
+func skeletonGroup(c rune) rune {
+	switch c {
+	case 'G', 'w', 'W', 'd', 'D', 'F', 'g', 'm', 's':
+		return c
+	case 'y', 'Y', 'u', 'U', 'r':
+		return 'y'
+	case 'Q', 'q':
+		return 'Q'
+	case 'M', 'L':
+		return 'M'
+	case 'E', 'c', 'e':
+		return 'E'
+	case 'b', 'B':
+		return 'B'
+	case 'h', 'H', 'K', 'k', 'j', 'J', 'C':
+		return 'H'
+	case 'S', 'A':
+		return 'S'
+	case 'z', 'Z', 'O', 'v', 'V', 'X', 'x':
+		return 'z'
+	}
+
+	return 0
+}
+
+// isDateGroup returns true if the group is part of the date, false if it
+// is part of the time.
+func isDateGroup(group rune) bool {
+	switch group {
+	case 'B', 'H', 'm', 's', 'S', 'z':
+		return false
+	}
+
+	return true
+}
+
+// isTextField returns true if the field is displayed with a label, false
+// if it is displayed as a number: MMM is "Jan" while MM is "01".
+func isTextField(item patternItem) bool {
+	switch item.Field {
+	case 'G', 'E', 'a', 'b', 'B', 'z', 'Z', 'O', 'v', 'V':
+		return true
+	case 'M', 'L', 'Q', 'q', 'e', 'c':
+		return item.Count >= 3
+	}
+
+	return false
+}
+
+// parseSkeleton parses a skeleton, the hour cycle letters j, J and C are
+// replaced by the hour letter given by the locale.
+func parseSkeleton(value string, hour rune) skeleton {
+	s := skeleton{}
+
+	for _, item := range parseDatePattern(value) {
+		if item.Field == 0 {
+			continue
+		}
+
+		switch item.Field {
+		case 'j', 'J', 'C':
+			item.Field = hour
+		}
+
+		group := skeletonGroup(item.Field)
+		if group == 0 {
+			continue
+		}
+
+		s[group] = item
+	}
+
+	return s
+}
+
+// distance computes the distance from the requested skeleton to the
+// candidate, 0 is an exact match.
+func (s skeleton) distance(candidate skeleton) int {
+	distance := 0
+
+	for group, requested := range s {
+		item, ok := candidate[group]
+		if !ok {
+			distance += skeletonMissingField
+			continue
+		}
+
+		if isTextField(requested) != isTextField(item) {
+			distance += skeletonMismatchType
+		}
+
+		if requested.Field != item.Field {
+			distance += skeletonMismatchLetter
+		}
+
+		if requested.Count > item.Count {
+			distance += requested.Count - item.Count
+		} else {
+			distance += item.Count - requested.Count
+		}
+	}
+
+	for group := range candidate {
+		if _, ok := s[group]; !ok {
+			distance += skeletonExtraField
+		}
+	}
+
+	return distance
+}
+
+// split returns the date and the time parts of the skeleton.
+func (s skeleton) split() (skeleton, skeleton) {
+	date, time := skeleton{}, skeleton{}
+
+	for group, item := range s {
+		if isDateGroup(group) {
+			date[group] = item
+		} else {
+			time[group] = item
+		}
+	}
+
+	return date, time
+}
+
+// glueLength returns the length of the dateTimeFormat used to combine the
+// date and the time parts of the skeleton.
+func (s skeleton) glueLength() string {
+	month, hasMonth := s['M']
+	_, hasWeekday := s['E']
+
+	switch {
+	case hasMonth && month.Count >= 4 && hasWeekday:
+		return "full"
+	case hasMonth && month.Count >= 4:
+		return "long"
+	case hasMonth && month.Count == 3:
+		return "medium"
+	}
+
+	return "short"
+}
+
+// skeletonMatch is the best candidate found for a requested skeleton.
+type skeletonMatch struct {
+	skeleton skeleton
+	value    string
+	distance int
+}
+
+// skeletonCandidate is a skeleton defined by the locale, with its fields.
+type skeletonCandidate struct {
+	value    string
+	skeleton skeleton
+}
+
+// parseCandidates parses the skeletons, the candidates are sorted so the
+// best match is stable.
+func parseCandidates(values []string, hour rune) []*skeletonCandidate {
+	sort.Strings(values)
+
+	candidates := make([]*skeletonCandidate, len(values))
+	for i, value := range values {
+		candidates[i] = &skeletonCandidate{value: value, skeleton: parseSkeleton(value, hour)}
+	}
+
+	return candidates
+}
+
+// bestSkeleton returns the candidate with the smallest distance.
+func bestSkeleton(requested skeleton, candidates []*skeletonCandidate) *skeletonMatch {
+	var best *skeletonMatch
+	for _, candidate := range candidates {
+		distance := requested.distance(candidate.skeleton)
+
+		if best == nil || distance < best.distance {
+			best = &skeletonMatch{skeleton: candidate.skeleton, value: candidate.value, distance: distance}
+		}
+
+		if distance == 0 {
+			break
+		}
+	}
+
+	return best
+}
+
+// calendarSkeletons are the skeletons and the interval skeletons of a
+// calendar, merged from the parent locales and the fallback calendars.
+type calendarSkeletons struct {
+	hour       rune
+	skeletons  map[string]string
+	intervals  map[string]map[string]string
+	candidates []*skeletonCandidate
+	// the candidates of the intervals
+	intervalCandidates []*skeletonCandidate
+}
+
+type calendarKey struct {
+	locale   *dto.Locale
+	calendar string
+}
+
+var skeletonCache sync.Map // calendarKey => *calendarSkeletons
+
+// getSkeletons returns the skeletons of the locale's calendar, they are
+// only merged and parsed once.
+func getSkeletons(locale *dto.Locale, calendar string) *calendarSkeletons {
+	key := calendarKey{locale: locale, calendar: calendar}
+	if s, ok := skeletonCache.Load(key); ok {
+		return s.(*calendarSkeletons)
+	}
+
+	s := &calendarSkeletons{
+		hour:      getHourLetter(locale, calendar),
+		skeletons: getCalendarSkeletons(locale, calendar),
+		intervals: getCalendarIntervals(locale, calendar),
+	}
+
+	values := make([]string, 0, len(s.skeletons))
+	for value := range s.skeletons {
+		values = append(values, value)
+	}
+
+	s.candidates = parseCandidates(values, s.hour)
+
+	values = make([]string, 0, len(s.intervals))
+	for value := range s.intervals {
+		values = append(values, value)
+	}
+
+	s.intervalCandidates = parseCandidates(values, s.hour)
+
+	cached, _ := skeletonCache.LoadOrStore(key, s)
+
+	return cached.(*calendarSkeletons)
+}
+
+// adjustPattern changes the field lengths of the pattern to match the
+// requested skeleton, ie: the "MMM d" pattern of the "MMMd" skeleton becomes
+// "MMMM d" for "MMMMd". The hour, minute and second lengths are kept, as
+// the locale defines the padding, and a field is only adjusted if the
+// numeric or text type is the same.
+func adjustPattern(items []patternItem, requested, matched skeleton) []patternItem {
+	adjusted := make([]patternItem, len(items))
+
+	for i, item := range items {
+		adjusted[i] = item
+
+		group := skeletonGroup(item.Field)
+		if group == 0 || group == 'H' || group == 'm' || group == 's' {
+			continue
+		}
+
+		field, ok := requested[group]
+		if !ok || field.Count == matched[group].Count {
+			continue
+		}
+
+		if isTextField(field) != isTextField(item) {
+			continue
+		}
+
+		adjusted[i].Count = field.Count
+	}
+
+	return adjusted
+}
+
+// formatPatternItems serializes the items to a pattern, the literals
+// containing letters or quotes are quoted.
+func formatPatternItems(items []patternItem) string {
+	b := strings.Builder{}
+
+	for _, item := range items {
+		if item.Field != 0 {
+			b.WriteString(strings.Repeat(string(item.Field), item.Count))
+			continue
+		}
+
+		if item.Literal == "'" {
+			b.WriteString("''")
+			continue
+		}
+
+		if strings.IndexFunc(item.Literal, func(c rune) bool { return isPatternLetter(c) || c == '\'' }) == -1 {
+			b.WriteString(item.Literal)
+			continue
+		}
+
+		b.WriteString("'" + strings.ReplaceAll(item.Literal, "'", "''") + "'")
+	}
+
+	return b.String()
+}
+
+// getHourLetter returns the hour letter preferred by the locale, from its
+// short time pattern.
+func getHourLetter(locale *dto.Locale, calendar string) rune {
+	for _, item := range parseDatePattern(getCalendarPattern(locale, calendar, "time_short")) {
+		if skeletonGroup(item.Field) == 'H' {
+			return item.Field
+		}
+	}
+
+	return 'H'
+}
+
+// getSkeletonPattern returns the best pattern available for the skeleton,
+// the date and time parts are matched separately if no single pattern
+// contains all the requested fields.
+func getSkeletonPattern(locale *dto.Locale, calendar, value string) string {
+	skeletons := getSkeletons(locale, calendar)
+	if pattern, ok := skeletons.skeletons[value]; ok {
+		return pattern
+	}
+
+	requested := parseSkeleton(value, skeletons.hour)
+
+	match := func(requested skeleton) *skeletonMatch {
+		best := bestSkeleton(requested, skeletons.candidates)
+		if best == nil {
+			return nil
+		}
+
+		items := compileDatePattern(skeletons.skeletons[best.value])
+		best.value = formatPatternItems(adjustPattern(items, requested, best.skeleton))
+
+		return best
+	}
+
+	best := match(requested)
+	if best == nil {
+		return value
+	}
+
+	if best.distance < skeletonMissingField {
+		return best.value
+	}
+
+	date, time := requested.split()
+	if len(date) == 0 || len(time) == 0 {
+		return best.value
+	}
+
+	datePattern, timePattern := match(date), match(time)
+
+	glue := getCalendarPattern(locale, calendar, "datetime_"+requested.glueLength())
+	if glue == "" {
+		glue = "{1} {0}"
+	}
+
+	// the glue is a pattern, so its quoted literals are kept
+	return strings.NewReplacer("{0}", timePattern.value, "{1}", datePattern.value).Replace(glue)
+}
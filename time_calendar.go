@@ -4,3 +4,50 @@
 // license that can be found in the LICENSE file.
 
 package golocales
+
+import (
+	"strings"
+)
+
+// DefaultCalendar is the calendar system used by the formatters.
+const DefaultCalendar = "gregorian"
+
+// combineDateTime applies a glue pattern like "{1} 'at' {0}", where {1} is
+// the date and {0} the time. The quoted parts of the pattern are literals.
+func combineDateTime(pattern, date, time string) string {
+	b := strings.Builder{}
+
+	isLiteral := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+
+		if c == '\'' {
+			// two quotes is an escaped quote
+			if i+1 < len(pattern) && pattern[i+1] == '\'' {
+				b.WriteByte('\'')
+				i++
+				continue
+			}
+
+			isLiteral = !isLiteral
+			continue
+		}
+
+		if !isLiteral && c == '{' && i+2 < len(pattern) && pattern[i+2] == '}' {
+			switch pattern[i+1] {
+			case '0':
+				b.WriteString(time)
+				i += 2
+				continue
+			case '1':
+				b.WriteString(date)
+				i += 2
+				continue
+			}
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/rande/golocales"
 	"github.com/rande/golocales/locales/en"
 	"github.com/rande/golocales/locales/fr"
 	"github.com/rande/golocales/locales/hi"
@@ -19,32 +20,35 @@ func Test_Calendar_DateFormat(t *testing.T) {
 	l_fr := fr.GetLocale()
 	l_en := en.GetLocale()
 	tm := time.Date(2015, 1, 30, 0, 0, 0, 0, time.UTC)
+	paris, _ := time.LoadLocation("Europe/Paris")
 
-	formatter_fr := l_fr.Calendars["gregorian"].Formatters["date_short"]
-	formatter_en := l_en.Calendars["gregorian"].Formatters["date_short"]
+	pattern_fr := l_fr.GetCalendarPattern("gregorian", "date_short")
+	pattern_en := l_en.GetCalendarPattern("gregorian", "date_short")
 
-	assert.Equal(t, "30/01/2015", formatter_fr(tm, "Europe/Paris"))
-	assert.Equal(t, "1/30/2015", formatter_en(tm, "Europe/Paris"))
+	assert.Equal(t, "30/01/2015", golocales.FormatPattern(l_fr, pattern_fr, tm, paris))
+	assert.Equal(t, "1/30/2015", golocales.FormatPattern(l_en, pattern_en, tm, paris))
 }
 
 func Test_Calendar_TimeFormat(t *testing.T) {
 	l_fr := fr.GetLocale()
 	l_en := en.GetLocale()
 	tm := time.Date(2015, 1, 30, 1, 1, 1, 0, time.UTC)
+	paris, _ := time.LoadLocation("Europe/Paris")
 
-	formatter_fr := l_fr.GetCalendarFormatter("gregorian", "time_short")
-	formatter_en := l_en.GetCalendarFormatter("gregorian", "time_short")
+	pattern_fr := l_fr.GetCalendarPattern("gregorian", "time_short")
+	pattern_en := l_en.GetCalendarPattern("gregorian", "time_short")
 
-	assert.Equal(t, "02:01", formatter_fr(tm, "Europe/Paris"))
-	assert.Equal(t, "2:01 am", formatter_en(tm, "Europe/Paris"))
+	assert.Equal(t, "02:01", golocales.FormatPattern(l_fr, pattern_fr, tm, paris))
+	assert.Equal(t, "2:01 am", golocales.FormatPattern(l_en, pattern_en, tm, paris))
 }
 
 func Test_Calendar_TimeShortFormat(t *testing.T) {
 	l_hi := hi.GetLocale()
 
 	tm := time.Date(2015, 1, 30, 1, 1, 1, 0, time.UTC)
+	paris, _ := time.LoadLocation("Europe/Paris")
 
-	formatter := l_hi.GetCalendarFormatter("gregorian", "time_short")
+	pattern := l_hi.GetCalendarPattern("gregorian", "time_short")
 
-	assert.Equal(t, "2:01 am", formatter(tm, "Europe/Paris"))
+	assert.Equal(t, "2:01 am", golocales.FormatPattern(l_hi, pattern, tm, paris))
 }
@@ -0,0 +1,483 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rande/golocales/dto"
+)
+
+// patternItem is a part of a date pattern: either a literal, or a field
+// with its length, ie: "MMM" is {Field: 'M', Count: 3}.
+type patternItem struct {
+	Field   rune
+	Count   int
+	Literal string
+}
+
+func isPatternLetter(c rune) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
+
+// parseDatePattern splits a LDML pattern into fields and literals, the
+// quoted parts are literals, and two quotes is an escaped quote.
+// http://www.unicode.org/reports/tr35/tr35-dates.html#Date_Format_Patterns
+func parseDatePattern(pattern string) []patternItem {
+	items := []patternItem{}
+	runes := []rune(pattern)
+
+	literal := strings.Builder{}
+	flush := func() {
+		if literal.Len() > 0 {
+			items = append(items, patternItem{Literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	isLiteral := false
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+
+		if c == '\'' {
+			if i+1 < len(runes) && runes[i+1] == '\'' {
+				literal.WriteRune('\'')
+				i++
+				continue
+			}
+
+			isLiteral = !isLiteral
+			continue
+		}
+
+		if isLiteral || !isPatternLetter(c) {
+			literal.WriteRune(c)
+			continue
+		}
+
+		flush()
+
+		count := 1
+		for i+1 < len(runes) && runes[i+1] == c {
+			count++
+			i++
+		}
+
+		items = append(items, patternItem{Field: c, Count: count})
+	}
+
+	flush()
+
+	return items
+}
+
+// maxPatternCache is the number of parsed patterns kept in memory, the
+// locales use a few hundred patterns. Once the cache is full, the new
+// patterns, like the ones given by the users, are parsed on each call.
+const maxPatternCache = 2048
+
+var (
+	patternCache     sync.Map // pattern => []patternItem
+	patternCacheSize atomic.Int64
+)
+
+// compileDatePattern returns the parsed pattern, a pattern is only parsed
+// once while the cache is not full.
+func compileDatePattern(pattern string) []patternItem {
+	if items, ok := patternCache.Load(pattern); ok {
+		return items.([]patternItem)
+	}
+
+	items := parseDatePattern(pattern)
+
+	if patternCacheSize.Load() < maxPatternCache {
+		if _, loaded := patternCache.LoadOrStore(pattern, items); !loaded {
+			patternCacheSize.Add(1)
+		}
+	}
+
+	return items
+}
+
+// FormatPattern formats the time with a LDML pattern, ie: "EEEE d MMMM y",
+// the labels are the ones of the locale. The time is converted into loc,
+// if loc is nil the location of the time is used. The calendar is the one
+// preferred in the locale's territory.
+// http://www.unicode.org/reports/tr35/tr35-dates.html#Date_Field_Symbol_Table
+func FormatPattern(locale *dto.Locale, pattern string, t time.Time, loc *time.Location) string {
+	if loc != nil {
+		t = t.In(loc)
+	}
+
+	return formatDatePattern(locale, getLocaleCalendar(locale), compileDatePattern(pattern), t)
+}
+
+// formatDatePattern formats the time with the pattern items, the time must
+// already be in the expected location.
+func formatDatePattern(locale *dto.Locale, calendar string, items []patternItem, t time.Time) string {
+	b := strings.Builder{}
+
+	calendar = resolveCalendar(calendar, t)
+
+	for _, item := range items {
+		if item.Field == 0 {
+			b.WriteString(item.Literal)
+			continue
+		}
+
+		b.WriteString(formatDateField(locale, calendar, item, t))
+	}
+
+	return b.String()
+}
+
+func formatDateField(locale *dto.Locale, calendar string, item patternItem, t time.Time) string {
+	if calendar != DefaultCalendar {
+		if value, ok := formatCalendarField(locale, calendar, item, t); ok {
+			return value
+		}
+	}
+
+	switch item.Field {
+	case 'G':
+		era := 1
+		if t.Year() <= 0 {
+			era = 0
+		}
+
+		return getEraLabel(locale, calendar, item.Count, era)
+	case 'y':
+		// the year of the era, there is no year 0: 1 BC is followed by 1 AD
+		year := t.Year()
+		if year <= 0 {
+			year = 1 - year
+		}
+
+		return formatYear(year, item.Count)
+	case 'Y':
+		year, _ := WeekOfYear(locale, t)
+
+		return formatYear(year, item.Count)
+	case 'u', 'U', 'r':
+		// the extended and related years are the astronomical year, the
+		// gregorian calendar does not have cyclic years
+		return formatYear(t.Year(), item.Count)
+	case 'Q', 'q':
+		quarter := (int(t.Month()) + 2) / 3
+		if item.Count <= 2 {
+			return fmt.Sprintf("%0*d", item.Count, quarter)
+		}
+
+		context := "format"
+		if item.Field == 'q' {
+			context = "stand-alone"
+		}
+
+		return getDateLabel(locale, calendar, "q", context, item.Count, quarter-1)
+	case 'M', 'L':
+		context := "format"
+		if item.Field == 'L' {
+			context = "stand-alone"
+		}
+
+		if item.Count <= 2 {
+			return fmt.Sprintf("%0*d", item.Count, int(t.Month()))
+		}
+
+		return getDateLabel(locale, calendar, "m", context, item.Count, int(t.Month())-1)
+	case 'w':
+		_, week := WeekOfYear(locale, t)
+
+		return fmt.Sprintf("%0*d", min(item.Count, 2), week)
+	case 'W':
+		data := getWeekData(GetLocaleTerritory(locale))
+
+		return fmt.Sprintf("%d", weekOfMonth(t, data.FirstDay, data.MinDays))
+	case 'd':
+		return fmt.Sprintf("%0*d", min(item.Count, 2), t.Day())
+	case 'D':
+		return fmt.Sprintf("%0*d", min(item.Count, 3), t.YearDay())
+	case 'F':
+		return fmt.Sprintf("%d", (t.Day()-1)/7+1)
+	case 'g':
+		// the modified julian day, the number of days since 1858-11-17
+		epoch := time.Date(1858, time.November, 17, 0, 0, 0, 0, time.UTC)
+
+		return fmt.Sprintf("%0*d", item.Count, daysBetween(epoch, t))
+	case 'E':
+		return getDateLabel(locale, calendar, "d", "format", max(item.Count, 3), int(t.Weekday()))
+	case 'e', 'c':
+		if item.Count <= 2 {
+			// the local day of week, 1 is the first day of the week
+			day := (int(t.Weekday())-int(FirstDayOfWeek(GetLocaleTerritory(locale)))+7)%7 + 1
+			if item.Field == 'c' {
+				return fmt.Sprintf("%d", day)
+			}
+
+			return fmt.Sprintf("%0*d", item.Count, day)
+		}
+
+		context := "format"
+		if item.Field == 'c' {
+			context = "stand-alone"
+		}
+
+		return getDateLabel(locale, calendar, "d", context, item.Count, int(t.Weekday()))
+	case 'a', 'b', 'B':
+		name := fmt.Sprintf("p_format_%s_%c", labelWidth(item.Count), item.Field)
+		// the day periods are only loaded for the gregorian calendar
+		if label := locale.GetDayPeriodLabel(DefaultCalendar, name, t.Hour()*100+t.Minute()); label != "" {
+			return label
+		}
+
+		return t.Format("PM")
+	case 'h':
+		hour := t.Hour() % 12
+		if hour == 0 {
+			hour = 12
+		}
+
+		return fmt.Sprintf("%0*d", min(item.Count, 2), hour)
+	case 'H':
+		return fmt.Sprintf("%0*d", min(item.Count, 2), t.Hour())
+	case 'K':
+		return fmt.Sprintf("%0*d", min(item.Count, 2), t.Hour()%12)
+	case 'k':
+		hour := t.Hour()
+		if hour == 0 {
+			hour = 24
+		}
+
+		return fmt.Sprintf("%0*d", min(item.Count, 2), hour)
+	case 'm':
+		return fmt.Sprintf("%0*d", min(item.Count, 2), t.Minute())
+	case 's':
+		return fmt.Sprintf("%0*d", min(item.Count, 2), t.Second())
+	case 'S':
+		// the fractional seconds are truncated to the requested length
+		fraction := fmt.Sprintf("%09d", t.Nanosecond())
+		if item.Count <= len(fraction) {
+			return fraction[:item.Count]
+		}
+
+		return fraction + strings.Repeat("0", item.Count-len(fraction))
+	case 'A':
+		millis := (t.Hour()*3600+t.Minute()*60+t.Second())*1000 + t.Nanosecond()/int(time.Millisecond)
+
+		return fmt.Sprintf("%0*d", item.Count, millis)
+	case 'z', 'Z', 'O', 'v', 'V', 'X', 'x':
+		return formatZone(locale, item, t)
+	}
+
+	return strings.Repeat(string(item.Field), item.Count)
+}
+
+// formatYear formats a year, yy is the year truncated to 2 digits, the
+// other lengths are the minimum number of digits.
+func formatYear(year, count int) string {
+	if count == 2 {
+		return fmt.Sprintf("%02d", year%100)
+	}
+
+	return fmt.Sprintf("%0*d", count, year)
+}
+
+// wallClock returns an UTC time with the same date and time values.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// weekYearStart returns the first day of the first week of the year, the
+// first week is the one with at least minDays days in the year.
+func weekYearStart(year int, firstDay time.Weekday, minDays int) time.Time {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(start.Weekday()) - int(firstDay) + 7) % 7
+
+	start = start.AddDate(0, 0, -offset)
+	if 7-offset < minDays {
+		start = start.AddDate(0, 0, 7)
+	}
+
+	return start
+}
+
+// weekOfYear returns the week-based year and the week of the year, the
+// days before the first week belong to the last week of the previous year.
+func weekOfYear(t time.Time, firstDay time.Weekday, minDays int) (int, int) {
+	year := t.Year()
+	date := time.Date(year, t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	start := weekYearStart(year, firstDay, minDays)
+	if date.Before(start) {
+		year--
+		start = weekYearStart(year, firstDay, minDays)
+	} else if next := weekYearStart(year+1, firstDay, minDays); !date.Before(next) {
+		year++
+		start = next
+	}
+
+	return year, daysBetween(start, date)/7 + 1
+}
+
+// weekOfMonth returns the week of the month, the days before the first week
+// are in the week 0.
+func weekOfMonth(t time.Time, firstDay time.Weekday, minDays int) int {
+	first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(first.Weekday()) - int(firstDay) + 7) % 7
+
+	week := (t.Day() + offset - 1) / 7
+	if 7-offset >= minDays {
+		week++
+	}
+
+	return week
+}
+
+// formatZone formats the time zone fields with the localized names, the
+// specific names (standard or daylight) depend on the instant. The missing
+// names fall back to the location format, then to the localized GMT format.
+func formatZone(locale *dto.Locale, item patternItem, t time.Time) string {
+	long := item.Count >= 4
+
+	switch item.Field {
+	case 'z':
+		return locale.FormatSpecificZoneName(t, long)
+	case 'v':
+		return locale.FormatGenericZoneName(t, long)
+	case 'O':
+		return locale.FormatGMT(t, long)
+	case 'Z':
+		_, offset := t.Zone()
+
+		switch item.Count {
+		case 4:
+			return locale.FormatGMT(t, true)
+		case 5:
+			return formatISOOffset(offset, 5, true)
+		}
+
+		return formatISOOffset(offset, 4, false)
+	case 'V':
+		switch item.Count {
+		case 1:
+			// the zones without a short id use the location format
+			if id := locale.FormatZoneShortId(t); id != "" {
+				return id
+			}
+		case 2:
+			return t.Location().String()
+		case 3:
+			return locale.GetExemplarCity(t.Location().String())
+		}
+
+		if name := locale.FormatZoneLocation(t); name != "" {
+			return name
+		}
+
+		return locale.FormatGMT(t, true)
+	case 'X', 'x':
+		_, offset := t.Zone()
+
+		return formatISOOffset(offset, item.Count, item.Field == 'X')
+	}
+
+	return ""
+}
+
+// formatISOOffset returns the ISO 8601 offset, the length selects the
+// format: +HH[mm], +HHmm, +HH:mm, +HHmm[ss] or +HH:mm[:ss]. The offset 0 is
+// "Z" if utc is true.
+func formatISOOffset(offset, count int, utc bool) string {
+	if offset == 0 && utc {
+		return "Z"
+	}
+
+	sign, hours, minutes, seconds := splitOffset(offset)
+
+	separator := ""
+	if count == 3 || count == 5 {
+		separator = ":"
+	}
+
+	s := fmt.Sprintf("%c%02d", sign, hours)
+
+	if count > 1 || minutes != 0 {
+		s += fmt.Sprintf("%s%02d", separator, minutes)
+	}
+
+	if count >= 4 && seconds != 0 {
+		s += fmt.Sprintf("%s%02d", separator, seconds)
+	}
+
+	return s
+}
+
+func splitOffset(offset int) (rune, int, int, int) {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+
+	return sign, offset / 3600, offset / 60 % 60, offset % 60
+}
+
+// labelWidth returns the width of a text field: 1 to 3 letters is the
+// abbreviated form, 4 the wide form and 5 the narrow form.
+func labelWidth(count int) string {
+	switch count {
+	case 4:
+		return "wide"
+	case 5:
+		return "narrow"
+	}
+
+	return "abbreviated"
+}
+
+func getDateLabel(locale *dto.Locale, calendar, prefix, context string, count, index int) string {
+	width := labelWidth(count)
+	if count > 5 {
+		// the short width is not loaded, use the abbreviated one
+		width = "abbreviated"
+	}
+
+	var labels []string
+	for _, c := range []string{getLabelCalendar(calendar), DefaultCalendar} {
+		labels = locale.GetCalendarLabels(c, fmt.Sprintf("%s_%s_%s", prefix, context, width))
+		if len(labels) == 0 && context != "format" {
+			labels = locale.GetCalendarLabels(c, fmt.Sprintf("%s_format_%s", prefix, width))
+		}
+
+		// the calendar can use the gregorian labels, ie: the week days
+		if len(labels) > 0 {
+			break
+		}
+	}
+
+	if index < 0 || index >= len(labels) {
+		return ""
+	}
+
+	return labels[index]
+}
+
+// getEraLabel returns the era label, G to GGG is the abbreviated form, GGGG
+// the wide form and GGGGG the narrow form. A missing width falls back to
+// the abbreviated form, then to the wide one.
+func getEraLabel(locale *dto.Locale, calendar string, count, index int) string {
+	for _, width := range []string{labelWidth(count), "abbreviated", "wide"} {
+		if label := locale.GetCalendarLabel(getLabelCalendar(calendar), "era_"+width, index); label != "" {
+			return label
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,171 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rande/golocales"
+	_ "github.com/rande/golocales/locales/all"
+	"github.com/rande/golocales/locales/fr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FormatPattern(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+
+	date := time.Date(2024, 3, 5, 14, 7, 9, 123456789, time.UTC)
+
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"EEEE d MMMM y", "mardi 5 mars 2024"},
+		{"yy-MM-dd", "24-03-05"},
+		{"LLLL cccc", "mars mardi"},
+		{"G qqqq", "ap. J.-C. 1er trimestre"},
+		{"h:mm", "3:07"},
+		{"HH:mm:ss.SSS", "15:07:09.123"},
+		{"A", "54429123"},
+		{"Y 'w'w W", "2024 w10 1"},
+		{"D DDD F e g", "65 065 1 2 60374"},
+		{"xxx Z ZZZZZ", "+01:00 +0100 +01:00"},
+		{"O OOOO ZZZZ", "UTC+1 UTC+01:00 UTC+01:00"},
+		{"VV VVV", "Europe/Paris Paris"},
+		{"h 'h' ''", "3 h '"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.want, golocales.FormatPattern(fr.GetLocale(), tt.pattern, date, paris))
+		})
+	}
+}
+
+func Test_FormatPattern_Edges(t *testing.T) {
+	locale := fr.GetLocale()
+
+	// the week-based year differs from the calendar year
+	assert.Equal(t, "2025-W01", golocales.FormatPattern(locale, "Y-'W'ww", time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), nil))
+	// the year of the era is used before the year 1
+	assert.Equal(t, "44 av. J.-C.", golocales.FormatPattern(locale, "y G", time.Date(-43, 3, 15, 0, 0, 0, 0, time.UTC), nil))
+	// UTC is Z with the X letter
+	assert.Equal(t, "Z +00", golocales.FormatPattern(locale, "X x", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), nil))
+}
+
+func Test_FormatPattern_TimeZone(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	winter := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	summer := time.Date(2024, 7, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		pattern string
+		t       time.Time
+		loc     *time.Location
+		want    string
+	}{
+		// the specific name depends on the instant
+		{"zzzz", winter, paris, "heure normale d’Europe centrale"},
+		{"zzzz", summer, paris, "heure d’été d’Europe centrale"},
+		{"vvvv", summer, paris, "heure d’Europe centrale"},
+		// no short names, so the localized GMT format is used
+		{"z", summer, paris, "UTC+2"},
+		{"v", winter, paris, "heure : Paris"},
+		{"VVVV", winter, newYork, "heure : New York"},
+		{"OOOO", winter, newYork, "UTC−05:00"},
+		{"O", winter, time.UTC, "UTC"},
+		// the short BCP 47 id, or the location format if it is not known
+		{"V", winter, paris, "frpar"},
+		{"V", winter, time.FixedZone("XYZ", 3600), "UTC+01:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.want, golocales.FormatPattern(fr.GetLocale(), tt.pattern, tt.t, tt.loc))
+		})
+	}
+}
+
+// patternFields returns the fields of a LDML pattern, ie: "d MMM 'de' y"
+// is d, MMM and y.
+func patternFields(pattern string) []string {
+	fields := []string{}
+	quoted := false
+
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		if c == '\'' {
+			quoted = !quoted
+			continue
+		}
+
+		if quoted || !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			continue
+		}
+
+		j := i
+		for j+1 < len(runes) && runes[j+1] == c {
+			j++
+		}
+
+		fields = append(fields, string(runes[i:j+1]))
+		i = j
+	}
+
+	return fields
+}
+
+func Test_FormatPattern_AllPatterns(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	times := []time.Time{
+		time.Date(2024, 1, 10, 0, 30, 0, 0, time.UTC),
+		time.Date(2024, 7, 14, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 31, 19, 45, 30, 0, time.UTC),
+	}
+
+	// every field of the CLDR patterns is formatted, an unknown field is
+	// written as is
+	for _, name := range golocales.Available() {
+		locale := golocales.Get(name)
+		if locale == nil {
+			// the data files registered by the tests can be invalid
+			continue
+		}
+
+		for system, calendar := range locale.Calendars {
+			patterns := []string{}
+			for key, pattern := range calendar.Patterns {
+				if !strings.Contains(pattern, "{") && key != "interval_fallback" {
+					patterns = append(patterns, pattern)
+				}
+			}
+
+			for _, pattern := range calendar.Skeletons {
+				patterns = append(patterns, pattern)
+			}
+
+			for _, intervals := range calendar.Intervals {
+				for _, pattern := range intervals {
+					patterns = append(patterns, pattern)
+				}
+			}
+
+			for _, pattern := range patterns {
+				for _, field := range patternFields(pattern) {
+					for _, tm := range times {
+						assert.NotEqual(t, field, golocales.FormatPattern(locale, field, tm, newYork), "%s %s %q", name, system, pattern)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rande/golocales/dto"
+)
+
+// DefaultTerritory is the territory used when a territory does not define
+// its own data, it represents the world.
+const DefaultTerritory = "001"
+
+func getWeekData(territory string) *dto.WeekData {
+	if data := dto.GetSupplemental().GetWeekData(territory); data != nil {
+		return data
+	}
+
+	// ISO 8601 weeks
+	return &dto.WeekData{
+		FirstDay:     time.Monday,
+		MinDays:      4,
+		WeekendStart: time.Saturday,
+		WeekendEnd:   time.Sunday,
+	}
+}
+
+// FirstDayOfWeek returns the first day of the week in the territory, ie:
+// sunday for US and monday for FR.
+func FirstDayOfWeek(territory string) time.Weekday {
+	return getWeekData(territory).FirstDay
+}
+
+// MinimalDaysInFirstWeek returns the minimal number of days the first week
+// of the year must have in the territory, 4 for ISO 8601.
+func MinimalDaysInFirstWeek(territory string) int {
+	return getWeekData(territory).MinDays
+}
+
+// IsWeekend returns true if the day of t is a weekend day in the territory,
+// the weekend can span over the end of the week: friday to saturday.
+func IsWeekend(t time.Time, territory string) bool {
+	data := getWeekData(territory)
+
+	day := (int(t.Weekday()) - int(data.WeekendStart) + 7) % 7
+	length := (int(data.WeekendEnd) - int(data.WeekendStart) + 7) % 7
+
+	return day <= length
+}
+
+// WeekOfYear returns the week-based year and the week of the year of t,
+// using the week rules of the locale's territory: 2024-12-29 is in the week
+// 52 of 2024 in France, and in the week 1 of 2025 in the US.
+func WeekOfYear(locale *dto.Locale, t time.Time) (int, int) {
+	data := getWeekData(GetLocaleTerritory(locale))
+
+	return weekOfYear(t, data.FirstDay, data.MinDays)
+}
+
+// GetLocaleTerritory returns the territory of the locale, the likely
+// territory is used for a locale without territory: fr is FR and sr_Latn
+// is RS.
+func GetLocaleTerritory(locale *dto.Locale) string {
+	// a region (FR) or a UN M.49 code (419)
+	if locale.Territory != "" {
+		return locale.Territory
+	}
+
+	tag := locale.Name
+	if tag == "root" {
+		tag = "und"
+	}
+
+	if likely := dto.GetSupplemental().GetLikelySubtags(tag); likely != "" {
+		parts := strings.Split(likely, "_")
+		if last := parts[len(parts)-1]; isTerritoryCode(last) {
+			return last
+		}
+	}
+
+	return DefaultTerritory
+}
+
+func isTerritoryCode(code string) bool {
+	if len(code) == 2 {
+		return strings.ToUpper(code) == code
+	}
+
+	return len(code) == 3 && strings.Trim(code, "0123456789") == ""
+}
@@ -0,0 +1,77 @@
+// Copyright © 2023 Thomas Rabaix <thomas.rabaix@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package golocales_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rande/golocales"
+	"github.com/rande/golocales/dto"
+	"github.com/rande/golocales/locales/en"
+	"github.com/rande/golocales/locales/fr"
+	"github.com/rande/golocales/locales/fr_CA"
+	"github.com/rande/golocales/locales/sr_Latn"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FirstDayOfWeek(t *testing.T) {
+	assert.Equal(t, time.Monday, golocales.FirstDayOfWeek("FR"))
+	assert.Equal(t, time.Sunday, golocales.FirstDayOfWeek("US"))
+	// the world value is used for unknown territories
+	assert.Equal(t, time.Monday, golocales.FirstDayOfWeek("ZZ"))
+}
+
+func Test_Supplemental(t *testing.T) {
+	// the supplemental data is registered by the root locale package
+	supplemental := dto.GetSupplemental()
+	assert.NotNil(t, supplemental)
+	assert.Equal(t, 4, supplemental.GetWeekData("FR").MinDays)
+	// the world value is used for unknown territories
+	assert.Same(t, supplemental.GetWeekData("001"), supplemental.GetWeekData("ZZ"))
+
+	// a nil supplemental data returns the default values
+	var empty *dto.Supplemental
+	assert.Nil(t, empty.GetWeekData("FR"))
+	assert.Equal(t, "Europe/Paris", empty.GetCanonicalZone("Europe/Paris"))
+}
+
+func Test_IsWeekend(t *testing.T) {
+	friday := time.Date(2024, 1, 12, 10, 0, 0, 0, time.UTC)
+	saturday := friday.AddDate(0, 0, 1)
+	sunday := friday.AddDate(0, 0, 2)
+
+	assert.False(t, golocales.IsWeekend(friday, "FR"))
+	assert.True(t, golocales.IsWeekend(saturday, "FR"))
+	assert.True(t, golocales.IsWeekend(sunday, "FR"))
+
+	// the weekend is friday in Iran
+	assert.True(t, golocales.IsWeekend(friday, "IR"))
+	assert.False(t, golocales.IsWeekend(sunday, "IR"))
+}
+
+func Test_GetLocaleTerritory(t *testing.T) {
+	assert.Equal(t, "CA", golocales.GetLocaleTerritory(fr_CA.GetLocale()))
+	// the likely subtags are used for a language
+	assert.Equal(t, "FR", golocales.GetLocaleTerritory(fr.GetLocale()))
+	assert.Equal(t, "US", golocales.GetLocaleTerritory(en.GetLocale()))
+	assert.Equal(t, "RS", golocales.GetLocaleTerritory(sr_Latn.GetLocale()))
+}
+
+func Test_WeekOfYear(t *testing.T) {
+	date := time.Date(2024, 12, 29, 10, 0, 0, 0, time.UTC)
+
+	year, week := golocales.WeekOfYear(fr.GetLocale(), date)
+	assert.Equal(t, 2024, year)
+	assert.Equal(t, 52, week)
+
+	year, week = golocales.WeekOfYear(en.GetLocale(), date)
+	assert.Equal(t, 2025, year)
+	assert.Equal(t, 1, week)
+
+	assert.Equal(t, "52 2024", golocales.FormatPattern(fr.GetLocale(), "w Y", date, nil))
+	assert.Equal(t, "1 2025", golocales.FormatPattern(en.GetLocale(), "w Y", date, nil))
+}